@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"event-booking-be/internal/models"
+)
+
+func listBookings(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("bookings list")
+	eventID := fs.Int("event", 0, "only bookings for this event")
+	userID := fs.Int("user", 0, "only bookings by this user")
+	status := fs.String("status", "", "only bookings in this status")
+	limit := fs.Int("limit", 50, "maximum number of bookings")
+	fs.Parse(args)
+
+	bookings, err := a.bookingService.ListBookings(ctx, models.BookingFilter{
+		EventID: *eventID,
+		UserID:  *userID,
+		Status:  models.BookingStatus(strings.ToUpper(*status)),
+		Limit:   *limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.output(bookings, func() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tREFERENCE\tUSER\tEVENT\tTICKETS\tTOTAL\tSTATUS\tCREATED")
+		for _, b := range bookings {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%.2f\t%s\t%s\n", b.ID, models.BookingReference(b.ID),
+				b.UserID, b.EventID, b.TicketCount, b.TotalPrice, b.Status, b.CreatedAt.Format(time.RFC3339))
+		}
+		w.Flush()
+	})
+}
+
+func showBooking(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("bookings show")
+	fs.Parse(args)
+
+	id, err := bookingIDArg(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	booking, err := a.bookingService.LookupBooking(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return a.output(booking, func() { printBooking(booking) })
+}
+
+func expireBooking(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("bookings expire")
+	fs.Parse(args)
+
+	id, err := bookingIDArg(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	return a.transitionBooking(ctx, id, a.bookingService.ForceExpireBooking)
+}
+
+func confirmBooking(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("bookings confirm")
+	fs.Parse(args)
+
+	id, err := bookingIDArg(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	return a.transitionBooking(ctx, id, a.bookingService.ForceConfirmBooking)
+}
+
+// transitionBooking applies transition and prints the booking as it stands
+// afterwards, read inside the transaction so --dry-run shows the outcome.
+func (a *app) transitionBooking(ctx context.Context, id int, transition func(context.Context, int) error) error {
+	var booking *models.BookingWithDetails
+
+	err := a.mutate(ctx, func(ctx context.Context) error {
+		if err := transition(ctx, id); err != nil {
+			return err
+		}
+
+		var err error
+		booking, err = a.bookingService.LookupBooking(ctx, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.output(booking, func() { printBooking(booking) })
+}
+
+func printBooking(b *models.BookingWithDetails) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%d\n", b.ID)
+	fmt.Fprintf(w, "Reference:\t%s\n", models.BookingReference(b.ID))
+	fmt.Fprintf(w, "Status:\t%s\n", b.Status)
+	fmt.Fprintf(w, "User:\t%d %s <%s>\n", b.UserID, b.UserName, b.UserEmail)
+	fmt.Fprintf(w, "Event:\t%d %s (%s)\n", b.EventID, b.EventName, b.EventDateTime.Format(time.RFC3339))
+	fmt.Fprintf(w, "Tickets:\t%d\n", b.TicketCount)
+	fmt.Fprintf(w, "Total:\t%.2f\n", b.TotalPrice)
+	fmt.Fprintf(w, "Expires:\t%s\n", b.ExpiresAt.Format(time.RFC3339))
+	printTime(w, "Confirmed:", b.ConfirmedAt)
+	printTime(w, "Cancelled:", b.CancelledAt)
+	printTime(w, "Expired:", b.ExpiredAt)
+	fmt.Fprintf(w, "Created:\t%s\n", b.CreatedAt.Format(time.RFC3339))
+	w.Flush()
+}
+
+func printTime(w *tabwriter.Writer, label string, t *time.Time) {
+	if t != nil {
+		fmt.Fprintf(w, "%s\t%s\n", label, t.Format(time.RFC3339))
+	}
+}
+
+func bookingIDArg(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("expected exactly one booking ID")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid booking ID %q", args[0])
+	}
+	return id, nil
+}
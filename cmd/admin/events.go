@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"event-booking-be/internal/importer"
+	"event-booking-be/internal/models"
+)
+
+func recountEvents(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("events recount")
+	all := fs.Bool("all", false, "recount every event")
+	fs.Parse(args)
+
+	var eventIDs []int
+	switch {
+	case *all:
+		events, err := a.eventService.GetAllEvents(ctx)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			eventIDs = append(eventIDs, event.ID)
+		}
+	case len(fs.Args()) == 1:
+		id, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid event ID %q", fs.Arg(0))
+		}
+		eventIDs = []int{id}
+	default:
+		return fmt.Errorf("expected an event ID or --all")
+	}
+
+	var recounts []*models.InventoryRecount
+	err := a.mutate(ctx, func(ctx context.Context) error {
+		for _, id := range eventIDs {
+			recount, err := a.bookingService.RecountInventory(ctx, id)
+			if err != nil {
+				return fmt.Errorf("event %d: %w", id, err)
+			}
+			recounts = append(recounts, recount)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.output(recounts, func() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "EVENT\tCAPACITY\tHELD\tBEFORE\tAFTER\tNOTE")
+		for _, r := range recounts {
+			note := ""
+			switch {
+			case r.Oversold > 0:
+				note = fmt.Sprintf("oversold by %d", r.Oversold)
+			case r.Changed:
+				note = "fixed"
+			}
+			fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%s\n", r.EventID, r.Capacity, r.HeldTickets, r.Before, r.After, note)
+		}
+		w.Flush()
+	})
+}
+
+// importEvents creates or updates an organizer's events from a CSV or JSON
+// file, as POST /events/import does. --dry-run is handled by the import
+// itself, so the report still shows what each row would do.
+func importEvents(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("events import")
+	organizerID := fs.Int("organizer", 0, "ID of the user who organizes the events")
+	format := fs.String("format", "", "csv or json (default: from the file extension)")
+	mode := fs.String("mode", models.ImportModeAtomic, "atomic or best_effort")
+	fs.Parse(args)
+
+	if len(fs.Args()) != 1 {
+		return fmt.Errorf("expected exactly one file")
+	}
+	if *organizerID == 0 {
+		return fmt.Errorf("--organizer is required")
+	}
+	if *mode != models.ImportModeAtomic && *mode != models.ImportModeBestEffort {
+		return fmt.Errorf("invalid mode %q", *mode)
+	}
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	if *format != importer.FormatCSV && *format != importer.FormatJSON {
+		return fmt.Errorf("can't tell the format of %s, use --format csv or json", path)
+	}
+
+	if _, err := a.userService.GetUser(ctx, *organizerID); err != nil {
+		return fmt.Errorf("organizer %d: %w", *organizerID, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rows, err := importer.ReadEvents(f, *format)
+	if err != nil {
+		return err
+	}
+
+	result, err := a.eventService.ImportEvents(ctx, *organizerID, rows, models.EventImportOptions{Mode: *mode, DryRun: a.dryRun})
+	if err != nil {
+		return err
+	}
+
+	err = a.output(result, func() {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ROW\tREF\tACTION\tEVENT\tERRORS")
+		for _, r := range result.Rows {
+			var problems []string
+			for _, fe := range r.Errors {
+				problems = append(problems, strings.TrimPrefix(fe.Field+": "+fe.Message, ": "))
+			}
+			event := ""
+			if r.EventID != 0 {
+				event = strconv.Itoa(r.EventID)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", r.Row, r.ExternalRef, r.Action, event, strings.Join(problems, "; "))
+		}
+		w.Flush()
+		fmt.Printf("\n%d created, %d updated, %d unchanged, %d failed\n", result.Created, result.Updated, result.Unchanged, result.Failed)
+		if !result.Applied {
+			fmt.Println("nothing was written")
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if result.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", result.Failed, len(result.Rows))
+	}
+	return nil
+}
+
+// exportAttendees prints an event's attendee list, as CSV unless --json is
+// given.
+func exportAttendees(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("attendees export")
+	status := fs.String("status", string(models.BookingStatusConfirmed), "comma-separated booking statuses to include")
+	fs.Parse(args)
+
+	if len(fs.Args()) != 1 {
+		return fmt.Errorf("expected exactly one event ID")
+	}
+	eventID, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid event ID %q", fs.Arg(0))
+	}
+
+	var statuses []models.BookingStatus
+	for _, s := range strings.Split(*status, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			statuses = append(statuses, models.BookingStatus(strings.ToUpper(s)))
+		}
+	}
+
+	attendees, err := a.bookingService.GetEventAttendees(ctx, eventID, statuses)
+	if err != nil {
+		return err
+	}
+
+	if a.json {
+		return a.output(attendees, nil)
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"booking_id", "reference", "user_id", "name", "email", "tickets", "status", "booked_at"})
+	for _, attendee := range attendees {
+		w.Write([]string{
+			strconv.Itoa(attendee.BookingID),
+			attendee.Reference,
+			strconv.Itoa(attendee.UserID),
+			attendee.Name,
+			attendee.Email,
+			strconv.Itoa(attendee.TicketCount),
+			string(attendee.Status),
+			attendee.BookedAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
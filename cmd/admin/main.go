@@ -0,0 +1,192 @@
+// Command admin runs operations tasks against the booking database: inspecting
+// and fixing bookings, recounting inventory, importing events, managing
+// admins, seeding demo data, migrating, and exporting attendee lists.
+//
+// Every command accepts --json for machine-readable output. Commands that
+// change data also accept --dry-run, which runs them in a transaction that is
+// rolled back, so the output shows what would happen without doing it.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/user"
+
+	"event-booking-be/internal/audit"
+	"event-booking-be/internal/config"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const usage = `usage: admin <command> [flags] [args]
+
+Commands:
+  bookings list [--event ID] [--user ID] [--status S] [--limit N]
+  bookings show <booking-id>
+  bookings expire <booking-id>       force-expire a pending booking
+  bookings confirm <booking-id>      force-confirm a pending booking
+  events recount <event-id> | --all  recount available tickets from bookings
+  events import <file> --organizer ID [--format csv|json] [--mode atomic|best_effort]
+  attendees export <event-id> [--status S,...]
+  users create-admin --name NAME --email EMAIL
+  seed [--users N] [--events N]
+  migrate up | down [steps] | status
+
+Flags available on every command:
+  --json       print the result as JSON
+  --dry-run    roll back all changes instead of committing them`
+
+var errDryRun = errors.New("dry run")
+
+type app struct {
+	db     *gorm.DB
+	json   bool
+	dryRun bool
+
+	bookingService service.BookingService
+	eventService   service.EventService
+	userService    service.UserService
+}
+
+type command func(ctx context.Context, a *app, args []string) error
+
+var commands = map[string]command{
+	"bookings list":      listBookings,
+	"bookings show":      showBooking,
+	"bookings expire":    expireBooking,
+	"bookings confirm":   confirmBooking,
+	"events recount":     recountEvents,
+	"events import":      importEvents,
+	"attendees export":   exportAttendees,
+	"users create-admin": createAdmin,
+	"seed":               seed,
+	"migrate":            runMigrate,
+}
+
+func main() {
+	log.SetFlags(0)
+
+	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to read .env: %v", err)
+	}
+
+	name, args := lookupCommand(os.Args[1:])
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	a, err := newApp()
+	if err != nil {
+		log.Fatalf("admin: %v", err)
+	}
+
+	ctx := audit.WithActor(context.Background(), audit.CLI(operator()))
+	if err := cmd(ctx, a, args); err != nil {
+		log.Fatalf("admin %s: %v", name, err)
+	}
+}
+
+// operator names whoever runs the CLI, for the audit log.
+func operator() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// lookupCommand splits the arguments into a command name (one or two words)
+// and the rest.
+func lookupCommand(args []string) (string, []string) {
+	if len(args) >= 2 {
+		if _, ok := commands[args[0]+" "+args[1]]; ok {
+			return args[0] + " " + args[1], args[2:]
+		}
+	}
+	if len(args) >= 1 {
+		return args[0], args[1:]
+	}
+	return "", nil
+}
+
+func newApp() (*app, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	eventRepo := repository.NewEventRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+
+	return &app{
+		db:             db,
+		bookingService: service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, auditRepo, db, cfg.BookingTimeoutMinutes, slog.Default()),
+		eventService:   service.NewEventService(eventRepo, userRepo, outboxRepo, auditRepo, db),
+		userService:    service.NewUserService(userRepo, auditRepo, db),
+	}, nil
+}
+
+// flags returns a flag set with the flags every command shares.
+func (a *app) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.BoolVar(&a.json, "json", false, "print the result as JSON")
+	fs.BoolVar(&a.dryRun, "dry-run", false, "roll back all changes instead of committing them")
+	return fs
+}
+
+// mutate runs fn in a transaction. With --dry-run the transaction is rolled
+// back after fn succeeds; domain events written to the outbox go with it, so
+// nothing is published either.
+func (a *app) mutate(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := repository.Transaction(ctx, a.db, func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		if a.dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		fmt.Fprintln(os.Stderr, "dry run: all changes rolled back")
+		return nil
+	}
+	return err
+}
+
+// output writes result as JSON with --json, and otherwise calls text.
+func (a *app) output(result interface{}, text func()) error {
+	if a.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	text()
+	return nil
+}
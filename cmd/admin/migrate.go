@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"event-booking-be/internal/migrate"
+	"event-booking-be/migrations"
+)
+
+type migrationStatus struct {
+	Version int64  `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+// runMigrate mirrors `api migrate`. With --dry-run, up and down only list the
+// migrations they would apply or roll back.
+func runMigrate(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("migrate")
+	fs.Parse(args)
+
+	sqlDB, err := a.db.DB()
+	if err != nil {
+		return err
+	}
+	migrator, err := migrate.NewMigrator(sqlDB, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	all, applied, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var affected []migrate.Migration
+	switch fs.Arg(0) {
+	case "status":
+		var statuses []migrationStatus
+		for _, m := range all {
+			statuses = append(statuses, migrationStatus{Version: m.Version, Name: m.Name, Applied: applied[m.Version]})
+		}
+		return a.output(statuses, func() {
+			for _, s := range statuses {
+				state := "pending"
+				if s.Applied {
+					state = "applied"
+				}
+				fmt.Printf("%06d_%-40s %s\n", s.Version, s.Name, state)
+			}
+		})
+	case "up":
+		if a.dryRun {
+			for _, m := range all {
+				if !applied[m.Version] {
+					affected = append(affected, m)
+				}
+			}
+		} else if affected, err = migrator.Up(ctx); err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if fs.NArg() > 1 {
+			if steps, err = strconv.Atoi(fs.Arg(1)); err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive number")
+			}
+		}
+		if a.dryRun {
+			for i := len(all) - 1; i >= 0 && len(affected) < steps; i-- {
+				if applied[all[i].Version] {
+					affected = append(affected, all[i])
+				}
+			}
+		} else if affected, err = migrator.Down(ctx, steps); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("expected up, down or status")
+	}
+
+	var statuses []migrationStatus
+	for _, m := range affected {
+		statuses = append(statuses, migrationStatus{Version: m.Version, Name: m.Name, Applied: (fs.Arg(0) == "up") != a.dryRun})
+	}
+	return a.output(statuses, func() {
+		verb := map[string]string{"up": "Applied", "down": "Rolled back"}[fs.Arg(0)]
+		if a.dryRun {
+			verb = "Would have " + map[string]string{"up": "applied", "down": "rolled back"}[fs.Arg(0)]
+		}
+		for _, s := range statuses {
+			fmt.Printf("%s %06d_%s\n", verb, s.Version, s.Name)
+		}
+		fmt.Printf("%d migration(s)\n", len(statuses))
+	})
+}
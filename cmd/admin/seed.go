@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event-booking-be/internal/models"
+)
+
+type seedResult struct {
+	Users    []*models.User    `json:"users"`
+	Events   []*models.Event   `json:"events"`
+	Bookings []*models.Booking `json:"bookings"`
+}
+
+// seed creates demo users, events and bookings through the regular services,
+// so the data goes through the same checks and emits the same domain events as
+// real traffic. Users are matched by email, so running it twice doesn't fail.
+func seed(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("seed")
+	numUsers := fs.Int("users", 10, "number of demo users")
+	numEvents := fs.Int("events", 3, "number of demo events")
+	fs.Parse(args)
+
+	result := &seedResult{}
+	err := a.mutate(ctx, func(ctx context.Context) error {
+		organizer, err := a.seedUser(ctx, "Demo Organizer", "organizer@demo.event-booking.local")
+		if err != nil {
+			return err
+		}
+
+		for i := 1; i <= *numUsers; i++ {
+			user, err := a.seedUser(ctx, fmt.Sprintf("Demo User %d", i), fmt.Sprintf("user%d@demo.event-booking.local", i))
+			if err != nil {
+				return err
+			}
+			result.Users = append(result.Users, user)
+		}
+
+		for i := 1; i <= *numEvents; i++ {
+			event, err := a.eventService.CreateEvent(ctx, organizer.ID, &models.CreateEventRequest{
+				Name:         fmt.Sprintf("Demo Event %d", i),
+				Description:  "Seeded by the admin CLI",
+				DateTime:     time.Now().AddDate(0, 0, 7*i).Truncate(time.Hour),
+				TotalTickets: 100,
+				TicketPrice:  float64(10 * i),
+			})
+			if err != nil {
+				return err
+			}
+			result.Events = append(result.Events, event)
+		}
+
+		// every user books one ticket per event; every other booking is paid
+		for _, event := range result.Events {
+			for j, user := range result.Users {
+				booking, err := a.bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{
+					EventID:     event.ID,
+					TicketCount: 1,
+				})
+				if err != nil {
+					return err
+				}
+				if j%2 == 0 {
+					if err := a.bookingService.ConfirmPayment(ctx, user.ID, booking.ID); err != nil {
+						return err
+					}
+					booking.Status = models.BookingStatusConfirmed
+				}
+				result.Bookings = append(result.Bookings, booking)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.output(result, func() {
+		fmt.Printf("Seeded %d users, %d events, %d bookings\n", len(result.Users), len(result.Events), len(result.Bookings))
+	})
+}
+
+func (a *app) seedUser(ctx context.Context, name, email string) (*models.User, error) {
+	if user, err := a.userService.GetUserByEmail(ctx, email); err == nil {
+		return user, nil
+	}
+	return a.userService.CreateUser(ctx, &models.CreateUserRequest{Name: name, Email: email})
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/validation"
+)
+
+func createAdmin(ctx context.Context, a *app, args []string) error {
+	fs := a.flags("users create-admin")
+	name := fs.String("name", "", "display name")
+	email := fs.String("email", "", "email address")
+	locale := fs.String("locale", "en", "email locale")
+	fs.Parse(args)
+
+	req := &models.CreateUserRequest{Name: *name, Email: *email, Locale: *locale}
+	if err := validation.Struct(req); err != nil {
+		return err
+	}
+
+	var user *models.User
+	err := a.mutate(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = a.userService.CreateAdmin(ctx, req)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.output(user, func() {
+		fmt.Printf("Admin %d: %s <%s>\n", user.ID, user.Name, user.Email)
+	})
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,14 +13,24 @@ import (
 
 	"event-booking-be/internal/config"
 	"event-booking-be/internal/handler"
+	"event-booking-be/internal/jobqueue"
+	"event-booking-be/internal/leader"
+	"event-booking-be/internal/logging"
+	"event-booking-be/internal/metrics"
+	"event-booking-be/internal/middleware"
 	"event-booking-be/internal/models"
+	"event-booking-be/internal/notification"
+	"event-booking-be/internal/openapi"
+	"event-booking-be/internal/outbox"
+	"event-booking-be/internal/ratelimit"
+	"event-booking-be/internal/realtime"
 	"event-booking-be/internal/repository"
 	"event-booking-be/internal/routes"
 	"event-booking-be/internal/service"
+	"event-booking-be/internal/tracing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
@@ -31,75 +43,217 @@ func main() {
 		log.Println("No .env file found")
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("Migrate: %v", err)
+		}
+		return
+	}
+
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	db, err := initGormDatabase(cfg)
+	logger, err := newLogger(cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up logging: %v", err)
+	}
+	// anything still using the log package goes through the same handler
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("Failed to set up tracing", err)
+	}
+
+	db, err := initGormDatabase(cfg, logger)
+	if err != nil {
+		fatal("Failed to connect to database", err)
+	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		fatal("Failed to instrument database", err)
+	}
+
+	if err := ensureSchemaCurrent(context.Background(), db); err != nil {
+		fatal("Refusing to start", err)
 	}
 
 	redisClient, err := initRedis(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		fatal("Failed to connect to Redis", err)
 	}
 	defer redisClient.Close()
+	if err := tracing.InstrumentRedis(redisClient); err != nil {
+		fatal("Failed to instrument Redis", err)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.RegisterDB(sqlDB)
+	}
+	metrics.RegisterRedis(redisClient)
 
 	// setup repos
 	eventRepo := repository.NewEventRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+
+	mailer, err := newMailer(cfg)
+	if err != nil {
+		fatal("Failed to set up mailer", err)
+	}
+	renderer, err := notification.NewRenderer(cfg.MailDefaultLocale)
+	if err != nil {
+		fatal("Failed to load email templates", err)
+	}
+	availabilityHub := realtime.NewHub(redisClient, cfg.RealtimeMaxSubscribers, logger)
 
 	// setup services
-	eventService := service.NewEventService(eventRepo)
-	userService := service.NewUserService(userRepo)
-	bookingService := service.NewBookingService(bookingRepo, eventRepo, db, cfg.BookingTimeoutMinutes)
+	eventService := service.TraceEventService(service.NewEventService(eventRepo, userRepo, outboxRepo, auditRepo, db))
+	userService := service.NewUserService(userRepo, auditRepo, db)
+	auditService := service.NewAuditService(auditRepo)
+	analyticsService := service.NewAnalyticsService(eventRepo, bookingRepo, userRepo)
+	calendarService := service.NewCalendarService(eventRepo, bookingRepo, userRepo, auditRepo, db, cfg.CalendarUIDDomain)
+	bookingService := service.TraceBookingService(
+		service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, auditRepo, db, cfg.BookingTimeoutMinutes, logger))
+	notificationService := service.NewNotificationService(bookingRepo, eventRepo, userRepo, jobRepo, renderer, mailer)
+	reminderService := service.NewReminderService(reminderRepo, eventRepo, notificationService, cfg.ReminderOffsets, 3, logger)
+	availabilityService := service.NewAvailabilityService(eventRepo, availabilityHub)
+	webhookService := service.NewWebhookService(webhookRepo, eventRepo, db, nil, service.WebhookConfig{
+		MaxAttempts:          cfg.WebhookMaxAttempts,
+		DisableAfterFailures: cfg.WebhookDisableAfterFailures,
+		Timeout:              time.Duration(cfg.WebhookTimeoutSeconds) * time.Second,
+		AllowPrivateNetworks: cfg.WebhookAllowPrivateNetworks,
+		Logger:               logger,
+	})
 
 	// setup handlers
 	eventHandler := handler.NewEventHandler(eventService)
 	userHandler := handler.NewUserHandler(userService)
 	bookingHandler := handler.NewBookingHandler(bookingService)
-
-	router := routes.NewRouter(userHandler, eventHandler, bookingHandler)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+	availabilityHandler := handler.NewAvailabilityHandler(availabilityService,
+		time.Duration(cfg.RealtimeHeartbeatSeconds)*time.Second)
+
+	rateLimiter := ratelimit.NewFallbackStore(
+		ratelimit.NewRedisStore(redisClient, "event-booking:ratelimit:"),
+		ratelimit.NewMemoryStore(),
+		logger,
+	)
+
+	router := routes.NewRouter(userHandler, eventHandler, bookingHandler, webhookHandler, availabilityHandler,
+		auditHandler, analyticsHandler, calendarHandler, userService, rateLimiter, routes.RateLimits{
+			Auth:        cfg.RateLimitAuth,
+			Events:      cfg.RateLimitEvents,
+			EventWrites: cfg.RateLimitEventWrites,
+			Bookings:    cfg.RateLimitBookings,
+			Webhooks:    cfg.RateLimitWebhooks,
+			Users:       cfg.RateLimitUsers,
+			Admin:       cfg.RateLimitAdmin,
+			Organizer:   cfg.RateLimitOrganizer,
+			Calendar:    cfg.RateLimitCalendar,
+		})
 
 	app := fiber.New(fiber.Config{
 		AppName:      "Event Booking API",
-		ErrorHandler: customErrorHandler,
+		ErrorHandler: handler.ErrorHandler,
 	})
 
-	setupMiddlewares(app)
-	
-	app.Get("/health", healthCheckHandler(db, redisClient))
-	
+	setupMiddlewares(app, logger)
+
+	elector := leader.NewElector(redisClient, leader.Config{
+		Key:    cfg.LeaderKey,
+		TTL:    time.Duration(cfg.LeaderLeaseSeconds) * time.Second,
+		Logger: logger,
+	})
+
+	app.Get("/health", healthCheckHandler(db, redisClient, elector))
+	app.Get(openapi.SpecPath, openapi.Handler())
+	app.Get(openapi.DocsPath, openapi.DocsHandler())
+
 	router.Setup(app)
 
-	// background worker for expired bookings
-	go startBookingWorker(bookingService)
+	metricsApp := startMetricsServer(cfg.MetricsAddr, logger)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+
+	jobWorker := jobqueue.NewWorker(jobRepo, jobqueue.Config{
+		PollInterval: time.Duration(cfg.JobPollIntervalMillis) * time.Millisecond,
+		Visibility:   time.Duration(cfg.JobVisibilityTimeoutSeconds) * time.Second,
+		Logger:       logger,
+	})
+	jobWorker.Handle(models.JobTypeExpireBooking, expireBookingJob(bookingService))
+	jobWorker.Handle(models.JobTypeSendEmail, sendEmailJob(notificationService))
+	go jobWorker.Run(workerCtx)
+
+	go availabilityHub.Run(workerCtx)
+
+	sinks := append(newOutboxSinks(cfg, redisClient, logger),
+		outbox.NewDispatchSink("webhooks", webhookService.Dispatch),
+		outbox.NewDispatchSink("notifications", notificationService.Dispatch),
+		outbox.NewDispatchSink("reminders", reminderService.Dispatch),
+		outbox.NewBestEffortSink("availability", availabilityService.Dispatch, logger),
+	)
+	relay := outbox.NewRelay(outboxRepo, db, sinks, outbox.RelayConfig{
+		PollInterval: time.Duration(cfg.OutboxPollIntervalSeconds) * time.Second,
+		Logger:       logger,
+	})
+
+	// singleton jobs: only the elected leader runs these
+	elector.Register("outbox-relay", relay.Run)
+	elector.Register("booking-expiry-sweep", func(ctx context.Context) {
+		startBookingSweeper(ctx, bookingService, logger)
+	})
+	go elector.Run(workerCtx)
+
+	go startWebhookWorker(workerCtx, webhookService, logger)
+	go startReminderWorker(workerCtx, reminderService, logger)
 
-	go gracefulShutdown(app, db, redisClient)
+	go gracefulShutdown(app, metricsApp, db, redisClient, stopWorkers, elector, shutdownTracing, logger)
 
 	addr := fmt.Sprintf(":%s", cfg.ServerPort)
-	log.Printf("Server starting on http://localhost%s", addr)
-	
+	logger.Info("Server starting", "addr", addr, "environment", cfg.Environment)
+
 	if err := app.Listen(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		fatal("Failed to start server", err)
 	}
 }
 
-func initGormDatabase(cfg *config.Config) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{})
+func newLogger(cfg *config.Config) (*slog.Logger, error) {
+	level, err := logging.ParseLevel(cfg.LogLevel)
 	if err != nil {
 		return nil, err
 	}
+	return logging.New(os.Stdout, level), nil
+}
 
-	// auto migrate - TODO: use proper migrations in production
-	if err := db.AutoMigrate(&models.Event{}, &models.User{}, &models.Booking{}); err != nil {
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+func initGormDatabase(cfg *config.Config, logger *slog.Logger) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseURL), &gorm.Config{
+		Logger: logging.GormLogger(logger, 200*time.Millisecond),
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	log.Println("Database connected (GORM)")
+	logger.Info("Database connected")
 	return db, nil
 }
 
@@ -118,38 +272,145 @@ func initRedis(cfg *config.Config) (*redis.Client, error) {
 		return nil, err
 	}
 
-	log.Println("Redis connected")
+	slog.Info("Redis connected")
 	return client, nil
 }
 
-func setupMiddlewares(app *fiber.App) {
+// startMetricsServer serves /metrics on addr, apart from the public API: the
+// business metrics break sales down by event. An empty addr turns it off.
+func startMetricsServer(addr string, logger *slog.Logger) *fiber.App {
+	if addr == "" {
+		return nil
+	}
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/metrics", metrics.Handler())
+	go func() {
+		logger.Info("Metrics server starting", "addr", addr)
+		if err := app.Listen(addr); err != nil {
+			logger.Error("Metrics server stopped", "error", err)
+		}
+	}()
+	return app
+}
+
+func setupMiddlewares(app *fiber.App, logger *slog.Logger) {
 	app.Use(recover.New())
-	app.Use(logger.New(logger.Config{
-		Format:     "${time} | ${status} | ${latency} | ${method} | ${path}\n",
-		TimeFormat: "15:04:05",
-	}))
+	app.Use(tracing.Middleware())
+	app.Use(middleware.RequestID())
+	app.Use(metrics.Middleware())
+	app.Use(middleware.RequestLogger(logger))
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
+		AllowOrigins:  "*",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, X-Request-ID",
+		ExposeHeaders: "X-Request-ID, Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset",
 	}))
 }
 
-func startBookingWorker(bookingService service.BookingService) {
-	ticker := time.NewTicker(1 * time.Minute)
+func expireBookingJob(bookingService service.BookingService) jobqueue.Handler {
+	return func(ctx context.Context, job *models.Job) error {
+		var payload models.ExpireBookingPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid expire booking payload: %w", err)
+		}
+		return bookingService.ExpireBooking(ctx, payload.BookingID)
+	}
+}
+
+func sendEmailJob(notificationService service.NotificationService) jobqueue.Handler {
+	return func(ctx context.Context, job *models.Job) error {
+		var payload models.SendEmailPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid send email payload: %w", err)
+		}
+		return notificationService.SendEmail(ctx, &payload)
+	}
+}
+
+// startBookingSweeper is a backstop for the expiry jobs: it picks up bookings
+// created before expiry jobs existed and any whose job ran out of attempts.
+func startBookingSweeper(ctx context.Context, bookingService service.BookingService, logger *slog.Logger) {
+	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
-	log.Println("Booking expiration worker started")
+	logger.Info("Booking expiry sweeper started")
 
-	for range ticker.C {
-		ctx := context.Background()
-		// TODO: maybe use a queue system like Redis for this instead of polling
+	for {
 		if err := bookingService.ProcessExpiredBookings(ctx); err != nil {
-			log.Printf("Error processing expired bookings: %v", err)
+			logger.ErrorContext(ctx, "Error processing expired bookings", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func startWebhookWorker(ctx context.Context, webhookService service.WebhookService, logger *slog.Logger) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	logger.Info("Webhook delivery worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := webhookService.ProcessDeliveries(ctx); err != nil {
+				logger.ErrorContext(ctx, "Error delivering webhooks", "error", err)
+			}
+		}
+	}
+}
+
+func startReminderWorker(ctx context.Context, reminderService service.ReminderService, logger *slog.Logger) {
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	logger.Info("Reminder worker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := reminderService.ProcessDue(ctx); err != nil {
+				logger.ErrorContext(ctx, "Error sending reminders", "error", err)
+			}
+		}
+	}
+}
+
+func newMailer(cfg *config.Config) (notification.Mailer, error) {
+	switch cfg.MailDriver {
+	case "smtp":
+		return notification.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom, cfg.SMTPTimeout), nil
+	case "memory":
+		return notification.NewMemoryMailer(), nil
+	default:
+		return notification.NewFileMailer(cfg.MailFileDir, cfg.MailFrom)
+	}
+}
+
+func newOutboxSinks(cfg *config.Config, redisClient *redis.Client, logger *slog.Logger) []outbox.Sink {
+	var sinks []outbox.Sink
+	for _, name := range cfg.OutboxSinks {
+		switch name {
+		case "log":
+			sinks = append(sinks, outbox.NewLogSink(logger))
+		case "redis":
+			sinks = append(sinks, outbox.NewRedisStreamSink(redisClient, cfg.OutboxRedisStream))
+		case "webhook":
+			sinks = append(sinks, outbox.NewWebhookSink(cfg.OutboxWebhookURL, 10*time.Second))
 		}
 	}
+	return sinks
 }
 
-func healthCheckHandler(db *gorm.DB, redisClient *redis.Client) fiber.Handler {
+func healthCheckHandler(db *gorm.DB, redisClient *redis.Client, elector *leader.Elector) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		ctx := context.Background()
 
@@ -164,47 +425,55 @@ func healthCheckHandler(db *gorm.DB, redisClient *redis.Client) fiber.Handler {
 			redisStatus = "error"
 		}
 
+		currentLeader, err := elector.Leader(ctx)
+		if err != nil {
+			currentLeader = ""
+		}
+
 		return c.JSON(fiber.Map{
 			"status":   "ok",
 			"database": dbStatus,
 			"redis":    redisStatus,
+			"leader": fiber.Map{
+				"instance":  elector.ID(),
+				"is_leader": elector.IsLeader(),
+				"current":   currentLeader,
+			},
 		})
 	}
 }
 
-func customErrorHandler(c *fiber.Ctx, err error) error {
-	code := fiber.StatusInternalServerError
-	message := "Internal Server Error"
-
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
-		message = e.Message
-	}
-
-	log.Printf("Error: %v | %s %s", err, c.Method(), c.Path())
-
-	return c.Status(code).JSON(fiber.Map{
-		"error":  message,
-		"status": code,
-	})
-}
-
-func gracefulShutdown(app *fiber.App, db *gorm.DB, redisClient *redis.Client) {
+func gracefulShutdown(app, metricsApp *fiber.App, db *gorm.DB, redisClient *redis.Client, stopWorkers context.CancelFunc, elector *leader.Elector, shutdownTracing func(context.Context) error, logger *slog.Logger) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 
 	<-quit
 
-	log.Println("Shutting down...")
+	logger.Info("Shutting down")
+
+	stopWorkers()
 
 	app.Shutdown()
-	
+	if metricsApp != nil {
+		metricsApp.Shutdown()
+	}
+
+	// hand the lease back so another replica takes over without waiting for the TTL
+	elector.Wait()
+
 	sqlDB, _ := db.DB()
 	if sqlDB != nil {
 		sqlDB.Close()
 	}
-	
+
 	redisClient.Close()
 
+	// flush spans still buffered in the batch exporter
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("Failed to flush traces", "error", err)
+	}
+
 	os.Exit(0)
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"event-booking-be/internal/config"
+	"event-booking-be/internal/migrate"
+	"event-booking-be/migrations"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status | create <name>"
+
+// runMigrate implements the `migrate` subcommand.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	// create only touches the migrations directory, no database needed
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return fmt.Errorf(migrateUsage)
+		}
+		up, down, err := migrate.Create("migrations", args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s\nCreated %s\n", up, down)
+		return nil
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	db, err := initGormDatabase(cfg, slog.Default())
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	migrator, err := migrate.NewMigrator(sqlDB, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migration(s)\n", len(applied))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive number")
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", len(rolledBack))
+	case "status":
+		all, applied, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range all {
+			state := "pending"
+			if applied[m.Version] {
+				state = "applied"
+			}
+			fmt.Printf("%06d_%-40s %s\n", m.Version, m.Name, state)
+		}
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+
+	return nil
+}
+
+// ensureSchemaCurrent refuses to start the API against a database that is
+// missing migrations.
+func ensureSchemaCurrent(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	migrator, err := migrate.NewMigrator(sqlDB, migrations.FS)
+	if err != nil {
+		return err
+	}
+	return migrator.EnsureCurrent(ctx)
+}
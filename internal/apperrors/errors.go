@@ -0,0 +1,115 @@
+// Package apperrors defines the domain errors repositories and services
+// return. Each carries a Kind, which the HTTP layer maps to a status code, a
+// stable code from utils for clients to branch on, and a message that is safe
+// to show them. Wrap them with %w to add context; errors.Is and errors.As see
+// through the wrapping.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+
+	"event-booking-be/internal/utils"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+)
+
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is matches errors by code, so an error built with Withf is still the
+// sentinel it came from.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Withf returns a copy of e with a more specific message.
+func (e *Error) Withf(format string, args ...interface{}) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: fmt.Sprintf(format, args...)}
+}
+
+var (
+	ErrInvalidBody  = New(KindInvalid, utils.INVALID_REQUEST_BODY, "Invalid request body")
+	ErrInvalidQuery = New(KindInvalid, utils.INVALID_QUERY, "Invalid query parameters")
+
+	ErrUserNotFound       = New(KindNotFound, utils.USER_NOT_FOUND, "user not found")
+	ErrEmailTaken         = New(KindConflict, utils.USER_ALREADY_EXISTS, "email already exists")
+	ErrInvalidCredentials = New(KindUnauthorized, utils.USER_INVALID_CREDENTIALS, "invalid credentials")
+	ErrForbidden          = New(KindForbidden, utils.FORBIDDEN, "you are not allowed to do this")
+
+	ErrEventNotFound = New(KindNotFound, utils.EVENT_NOT_FOUND, "event not found")
+
+	ErrBookingNotFound   = New(KindNotFound, utils.BOOKING_NOT_FOUND, "booking not found")
+	ErrNotEnoughTickets  = New(KindConflict, utils.BOOKING_NOT_ENOUGH_TICKETS, "not enough tickets available")
+	ErrBookingCancelled  = New(KindConflict, utils.BOOKING_ALREADY_CANCELLED, "booking is already cancelled")
+	ErrBookingConfirmed  = New(KindConflict, utils.BOOKING_ALREADY_CONFIRMED, "booking is already confirmed")
+	ErrBookingExpired    = New(KindConflict, utils.BOOKING_EXPIRED, "booking is already expired")
+	ErrBookingNotExpired = New(KindConflict, utils.BOOKING_NOT_EXPIRED, "booking has not expired yet")
+	ErrNotConfirmed      = New(KindConflict, utils.BOOKING_NOT_CONFIRMED, "booking is not confirmed")
+	ErrAlreadyCheckedIn  = New(KindConflict, utils.BOOKING_ALREADY_CHECKED_IN, "booking is already checked in")
+	ErrUnknownTicketType = New(KindInvalid, utils.BOOKING_UNKNOWN_TICKET_TYPE, "unknown ticket type")
+
+	ErrOrderLimitExceeded      = New(KindInvalid, utils.BOOKING_ORDER_LIMIT_EXCEEDED, "too many tickets in one order")
+	ErrInstrumentRequired      = New(KindInvalid, utils.BOOKING_INSTRUMENT_REQUIRED, "payment_instrument is required for this event")
+	ErrUserLimitExceeded       = New(KindConflict, utils.BOOKING_USER_LIMIT_EXCEEDED, "ticket limit per user reached")
+	ErrInstrumentLimitExceeded = New(KindConflict, utils.BOOKING_INSTRUMENT_LIMIT_EXCEEDED, "ticket limit per payment instrument reached")
+	ErrDomainLimitExceeded     = New(KindConflict, utils.BOOKING_DOMAIN_LIMIT_EXCEEDED, "ticket limit per email domain reached")
+
+	ErrInvalidTimezone = New(KindInvalid, utils.ANALYTICS_INVALID_TIMEZONE, "unknown timezone")
+	ErrInvalidRange    = New(KindInvalid, utils.ANALYTICS_INVALID_RANGE, "invalid analytics range")
+
+	ErrInvalidExportColumn = New(KindInvalid, utils.EXPORT_INVALID_COLUMN, "unknown export column")
+	ErrInvalidImportFile   = New(KindInvalid, utils.IMPORT_INVALID_FILE, "invalid import file")
+
+	ErrWebhookNotFound = New(KindNotFound, utils.WEBHOOK_NOT_FOUND, "webhook subscription not found")
+	ErrInvalidWebhook  = New(KindInvalid, utils.WEBHOOK_INVALID_REQUEST, "invalid webhook")
+)
+
+// BookingState returns the error for an operation that needs a pending
+// booking but found it in status (CONFIRMED, CANCELLED or EXPIRED).
+func BookingState(status string) error {
+	switch status {
+	case "CONFIRMED":
+		return ErrBookingConfirmed
+	case "CANCELLED":
+		return ErrBookingCancelled
+	case "EXPIRED":
+		return ErrBookingExpired
+	}
+	return fmt.Errorf("unexpected booking status %q", status)
+}
+
+// KindOf returns the kind of the first domain error in err's chain, or
+// KindInternal if there is none.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindInternal
+}
+
+func IsNotFound(err error) bool {
+	return KindOf(err) == KindNotFound
+}
@@ -0,0 +1,111 @@
+// Package audit carries who is acting, and for which request, through the
+// context, and computes the field-level diffs stored in the audit log.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+const (
+	ActorUser   = "user"
+	ActorCLI    = "cli"
+	ActorSystem = "system"
+)
+
+// Actor is whoever caused a change: an API user, an operator running the
+// admin CLI, or the system itself (expiry jobs and sweeps).
+type Actor struct {
+	Type string
+	ID   *int
+	Name string
+}
+
+func User(id int) Actor {
+	return Actor{Type: ActorUser, ID: &id}
+}
+
+func CLI(name string) Actor {
+	return Actor{Type: ActorCLI, Name: name}
+}
+
+var System = Actor{Type: ActorSystem}
+
+type actorKey struct{}
+type requestIDKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor attached to ctx, or System if there is none.
+func ActorFrom(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	return System
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Change is one field's value before and after a mutation.
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// ignoredFields are bookkeeping that follows from the other changes and
+// would only add noise.
+var ignoredFields = map[string]bool{
+	"updated_at": true,
+	"sequence":   true, // bumped with name, description and date_time
+}
+
+// Diff compares the JSON encodings of before and after and returns the
+// fields that differ. Either may be nil, for creations and deletions.
+func Diff(before, after interface{}) (map[string]Change, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]Change{}
+	for key, value := range b {
+		if !ignoredFields[key] && !reflect.DeepEqual(value, a[key]) {
+			changes[key] = Change{Before: value, After: a[key]}
+		}
+	}
+	for key, value := range a {
+		if _, seen := b[key]; !seen && !ignoredFields[key] && value != nil {
+			changes[key] = Change{After: value}
+		}
+	}
+	return changes, nil
+}
+
+func fields(v interface{}) (map[string]interface{}, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return map[string]interface{}{}, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -1,15 +1,19 @@
 package config
 
 import (
+	"event-booking-be/internal/ratelimit"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	ServerPort  string
 	Environment string
 	DatabaseURL string
+	LogLevel    string
 
 	RedisAddr     string
 	RedisPassword string
@@ -19,23 +23,160 @@ type Config struct {
 	JWTExpirationMinutes int
 
 	BookingTimeoutMinutes int
+
+	OutboxSinks               []string
+	OutboxRedisStream         string
+	OutboxWebhookURL          string
+	OutboxPollIntervalSeconds int
+
+	WebhookMaxAttempts          int
+	WebhookDisableAfterFailures int
+	WebhookTimeoutSeconds       int
+	WebhookAllowPrivateNetworks bool
+
+	MailDriver        string
+	MailFrom          string
+	MailFileDir       string
+	MailDefaultLocale string
+	SMTPHost          string
+	SMTPPort          int
+	SMTPUsername      string
+	SMTPPassword      string
+	SMTPTimeout       time.Duration
+
+	ReminderOffsets []time.Duration
+
+	RealtimeMaxSubscribers   int
+	RealtimeHeartbeatSeconds int
+
+	JobPollIntervalMillis       int
+	JobVisibilityTimeoutSeconds int
+
+	LeaderKey          string
+	LeaderLeaseSeconds int
+
+	TracingExporter    string
+	TracingServiceName string
+	TracingSampleRatio float64
+
+	MetricsAddr string
+
+	RateLimitAuth        ratelimit.Limit
+	RateLimitEvents      ratelimit.Limit
+	RateLimitEventWrites ratelimit.Limit
+	RateLimitBookings    ratelimit.Limit
+	RateLimitWebhooks    ratelimit.Limit
+	RateLimitUsers       ratelimit.Limit
+	RateLimitAdmin       ratelimit.Limit
+	RateLimitOrganizer   ratelimit.Limit
+	RateLimitCalendar    ratelimit.Limit
+
+	CalendarUIDDomain string
 }
 
 func LoadConfig() (*Config, error) {
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
 	jwtExpiration, _ := strconv.Atoi(getEnv("JWT_EXPIRATION_MINUTES", "60"))
 	bookingTimeout, _ := strconv.Atoi(getEnv("BOOKING_TIMEOUT_MINUTES", "15"))
+	outboxPollInterval, _ := strconv.Atoi(getEnv("OUTBOX_POLL_INTERVAL_SECONDS", "2"))
+	webhookMaxAttempts, _ := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "8"))
+	webhookDisableAfter, _ := strconv.Atoi(getEnv("WEBHOOK_DISABLE_AFTER_FAILURES", "20"))
+	webhookTimeout, _ := strconv.Atoi(getEnv("WEBHOOK_TIMEOUT_SECONDS", "10"))
+	webhookAllowPrivate, _ := strconv.ParseBool(getEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "false"))
+	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "1025"))
+	smtpTimeout, _ := strconv.Atoi(getEnv("SMTP_TIMEOUT_SECONDS", "30"))
+
+	realtimeMaxSubscribers, _ := strconv.Atoi(getEnv("REALTIME_MAX_SUBSCRIBERS", "1000"))
+	realtimeHeartbeat, _ := strconv.Atoi(getEnv("REALTIME_HEARTBEAT_SECONDS", "15"))
+
+	jobPollInterval, _ := strconv.Atoi(getEnv("JOB_POLL_INTERVAL_MS", "1000"))
+	jobVisibilityTimeout, _ := strconv.Atoi(getEnv("JOB_VISIBILITY_TIMEOUT_SECONDS", "60"))
+	leaderLease, _ := strconv.Atoi(getEnv("LEADER_LEASE_SECONDS", "15"))
+
+	tracingSampleRatio, err := strconv.ParseFloat(getEnv("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRACING_SAMPLE_RATIO: %w", err)
+	}
+
+	reminderOffsets, err := parseDurations(getEnvList("REMINDER_OFFSETS", "168h,2h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REMINDER_OFFSETS: %w", err)
+	}
+
+	var rateLimitErr error
+	rateLimit := func(key, defaultValue string) ratelimit.Limit {
+		limit, err := ratelimit.ParseLimit(getEnv(key, defaultValue))
+		if err != nil && rateLimitErr == nil {
+			rateLimitErr = fmt.Errorf("invalid %s: %w", key, err)
+		}
+		return limit
+	}
+
+	environment := getEnv("ENVIRONMENT", "development")
 
 	config := &Config{
 		ServerPort:            getEnv("SERVER_PORT", "8080"),
-		Environment:           getEnv("ENVIRONMENT", "development"),
+		Environment:           environment,
 		DatabaseURL:           getEnv("DATABASE_URL", ""),
+		LogLevel:              getEnv("LOG_LEVEL", defaultLogLevel(environment)),
 		RedisAddr:             getEnv("REDIS_ADDR", "localhost:6379"),
 		RedisPassword:         getEnv("REDIS_PASSWORD", ""),
 		RedisDB:               redisDB,
 		JWTSecret:             getEnv("JWT_SECRET", "AAA"),
 		JWTExpirationMinutes:  jwtExpiration,
 		BookingTimeoutMinutes: bookingTimeout,
+
+		OutboxSinks:               getEnvList("OUTBOX_SINKS", "log"),
+		OutboxRedisStream:         getEnv("OUTBOX_REDIS_STREAM", "booking-events"),
+		OutboxWebhookURL:          getEnv("OUTBOX_WEBHOOK_URL", ""),
+		OutboxPollIntervalSeconds: outboxPollInterval,
+
+		WebhookMaxAttempts:          webhookMaxAttempts,
+		WebhookDisableAfterFailures: webhookDisableAfter,
+		WebhookTimeoutSeconds:       webhookTimeout,
+		WebhookAllowPrivateNetworks: webhookAllowPrivate,
+
+		MailDriver:        getEnv("MAIL_DRIVER", "file"),
+		MailFrom:          getEnv("MAIL_FROM", "Event Booking <no-reply@event-booking.local>"),
+		MailFileDir:       getEnv("MAIL_FILE_DIR", "tmp/mail"),
+		MailDefaultLocale: getEnv("MAIL_DEFAULT_LOCALE", "en"),
+		SMTPHost:          getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:          smtpPort,
+		SMTPUsername:      getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:      getEnv("SMTP_PASSWORD", ""),
+		SMTPTimeout:       time.Duration(smtpTimeout) * time.Second,
+
+		ReminderOffsets: reminderOffsets,
+
+		RealtimeMaxSubscribers:   realtimeMaxSubscribers,
+		RealtimeHeartbeatSeconds: realtimeHeartbeat,
+
+		JobPollIntervalMillis:       jobPollInterval,
+		JobVisibilityTimeoutSeconds: jobVisibilityTimeout,
+
+		LeaderKey:          getEnv("LEADER_KEY", "event-booking:leader"),
+		LeaderLeaseSeconds: leaderLease,
+
+		TracingExporter:    getEnv("TRACING_EXPORTER", "none"),
+		TracingServiceName: getEnv("TRACING_SERVICE_NAME", "event-booking-be"),
+		TracingSampleRatio: tracingSampleRatio,
+
+		MetricsAddr: getEnv("METRICS_ADDR", "127.0.0.1:9090"),
+
+		RateLimitAuth:        rateLimit("RATE_LIMIT_AUTH", "10/1m"),
+		RateLimitEvents:      rateLimit("RATE_LIMIT_EVENTS", "300/1m"),
+		RateLimitEventWrites: rateLimit("RATE_LIMIT_EVENT_WRITES", "60/1m"),
+		RateLimitBookings:    rateLimit("RATE_LIMIT_BOOKINGS", "30/1m"),
+		RateLimitWebhooks:    rateLimit("RATE_LIMIT_WEBHOOKS", "60/1m"),
+		RateLimitUsers:       rateLimit("RATE_LIMIT_USERS", "120/1m"),
+		RateLimitAdmin:       rateLimit("RATE_LIMIT_ADMIN", "60/1m"),
+		RateLimitOrganizer:   rateLimit("RATE_LIMIT_ORGANIZER", "60/1m"),
+		RateLimitCalendar:    rateLimit("RATE_LIMIT_CALENDAR", "60/1m"),
+
+		CalendarUIDDomain: getEnv("CALENDAR_UID_DOMAIN", "event-booking.local"),
+	}
+	if rateLimitErr != nil {
+		return nil, rateLimitErr
 	}
 
 	if err := config.Validate(); err != nil {
@@ -49,12 +190,72 @@ func (c *Config) Validate() error {
 	if c.DatabaseURL == "" {
 		return fmt.Errorf("DATABASE_URL is required")
 	}
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("unknown log level %q", c.LogLevel)
+	}
+	switch c.TracingExporter {
+	case "none", "stdout", "otlp":
+	default:
+		return fmt.Errorf("unknown tracing exporter %q", c.TracingExporter)
+	}
+	switch c.MailDriver {
+	case "smtp", "file", "memory":
+	default:
+		return fmt.Errorf("unknown mail driver %q", c.MailDriver)
+	}
+	for _, sink := range c.OutboxSinks {
+		switch sink {
+		case "log", "redis":
+		case "webhook":
+			if c.OutboxWebhookURL == "" {
+				return fmt.Errorf("OUTBOX_WEBHOOK_URL is required for the webhook outbox sink")
+			}
+		default:
+			return fmt.Errorf("unknown outbox sink %q", sink)
+		}
+	}
 	return nil
 }
 
+// defaultLogLevel logs queries and other detail in development only.
+func defaultLogLevel(environment string) string {
+	if environment == "development" {
+		return "debug"
+	}
+	return "info"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvList reads a comma-separated list, dropping empty entries.
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func parseDurations(values []string) ([]time.Duration, error) {
+	durations := make([]time.Duration, 0, len(values))
+	for _, value := range values {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("duration %q must be positive", value)
+		}
+		durations = append(durations, d)
+	}
+	return durations, nil
+}
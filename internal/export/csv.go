@@ -0,0 +1,43 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) writeRow(values []string, numeric func(i int) bool) error {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = value
+		if !numeric(i) {
+			escaped[i] = neutralizeFormula(value)
+		}
+	}
+	return c.w.Write(escaped)
+}
+
+func (c *csvWriter) close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// neutralizeFormula keeps spreadsheet apps from evaluating a value a user
+// typed, such as a name starting with "=", as a formula.
+func neutralizeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,139 @@
+// Package export writes attendee lists as CSV or XLSX, one row at a time, so
+// a list of any length is never held in memory.
+package export
+
+import (
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Column is one exportable attendee field.
+type Column struct {
+	Key     string
+	Header  string
+	numeric bool
+	value   func(*models.Attendee) string
+}
+
+// Columns lists every column, in the default order.
+var Columns = []Column{
+	{Key: "booking_id", Header: "Booking ID", numeric: true, value: func(a *models.Attendee) string { return strconv.Itoa(a.BookingID) }},
+	{Key: "reference", Header: "Reference", value: func(a *models.Attendee) string { return a.Reference }},
+	{Key: "name", Header: "Name", value: func(a *models.Attendee) string { return a.Name }},
+	{Key: "email", Header: "Email", value: func(a *models.Attendee) string { return a.Email }},
+	{Key: "ticket_count", Header: "Tickets", numeric: true, value: func(a *models.Attendee) string { return strconv.Itoa(a.TicketCount) }},
+	{Key: "ticket_type", Header: "Ticket Type", value: func(a *models.Attendee) string { return a.TicketType }},
+	{Key: "status", Header: "Status", value: func(a *models.Attendee) string { return string(a.Status) }},
+	{Key: "booked_at", Header: "Booked At", value: func(a *models.Attendee) string { return a.BookedAt.UTC().Format(time.RFC3339) }},
+	{Key: "check_in_status", Header: "Check-in Status", value: checkInStatus},
+	{Key: "checked_in_at", Header: "Checked In At", value: func(a *models.Attendee) string {
+		if a.CheckedInAt == nil {
+			return ""
+		}
+		return a.CheckedInAt.UTC().Format(time.RFC3339)
+	}},
+}
+
+func checkInStatus(a *models.Attendee) string {
+	if a.CheckedInAt == nil {
+		return "not_checked_in"
+	}
+	return "checked_in"
+}
+
+// SelectColumns returns the columns named in keys, a comma-separated list, in
+// that order; an empty list selects them all.
+func SelectColumns(keys string) ([]Column, error) {
+	if strings.TrimSpace(keys) == "" {
+		return Columns, nil
+	}
+
+	var selected []Column
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		column, ok := lookup(key)
+		if !ok {
+			return nil, apperrors.ErrInvalidExportColumn.Withf("unknown column %q", key)
+		}
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
+
+func lookup(key string) (Column, bool) {
+	for _, column := range Columns {
+		if column.Key == key {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+// ContentType returns the MIME type of format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer writes attendees as rows under a header row.
+type Writer struct {
+	rows    rowWriter
+	columns []Column
+	values  []string
+}
+
+type rowWriter interface {
+	writeRow(values []string, numeric func(i int) bool) error
+	close() error
+}
+
+// NewWriter writes the header row for columns to w in format and returns a
+// Writer for the rest. Close must be called to finish the file.
+func NewWriter(w io.Writer, format string, columns []Column) (*Writer, error) {
+	var rows rowWriter
+	switch format {
+	case FormatCSV:
+		rows = newCSVWriter(w)
+	case FormatXLSX:
+		xlsx, err := newXLSXWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		rows = xlsx
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
+	if err := rows.writeRow(headers, func(int) bool { return false }); err != nil {
+		return nil, err
+	}
+
+	return &Writer{rows: rows, columns: columns, values: make([]string, len(columns))}, nil
+}
+
+func (w *Writer) Write(attendee *models.Attendee) error {
+	for i, column := range w.columns {
+		w.values[i] = column.value(attendee)
+	}
+	return w.rows.writeRow(w.values, func(i int) bool { return w.columns[i].numeric })
+}
+
+func (w *Writer) Close() error {
+	return w.rows.close()
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/utils"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+func (h *AnalyticsHandler) GetEventAnalytics(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.EVENT_INVALID_ID, "Invalid event ID")
+	}
+
+	rng, err := analyticsRange(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	analytics, err := h.analyticsService.GetEventAnalytics(c.UserContext(), userID, id, rng)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, analytics)
+}
+
+func (h *AnalyticsHandler) CompareEvents(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	rng, err := analyticsRange(c)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	summaries, err := h.analyticsService.CompareEvents(c.UserContext(), userID, rng)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, summaries)
+}
+
+func analyticsRange(c *fiber.Ctx) (models.AnalyticsRange, error) {
+	var query models.AnalyticsQuery
+	if err := bindQuery(c, &query); err != nil {
+		return models.AnalyticsRange{}, err
+	}
+
+	rng := models.AnalyticsRange{Interval: query.Interval, Timezone: query.Timezone}
+	// validated as RFC 3339 by bindQuery
+	if query.From != "" {
+		rng.From, _ = time.Parse(time.RFC3339, query.From)
+	}
+	if query.To != "" {
+		rng.To, _ = time.Parse(time.RFC3339, query.To)
+	}
+	return rng, nil
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/service"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) ListAuditLogs(c *fiber.Ctx) error {
+	var query models.AuditLogQuery
+	if err := bindQuery(c, &query); err != nil {
+		return errorResponse(c, err)
+	}
+
+	filter := models.AuditLogFilter{
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+		ActorID:    query.ActorID,
+		Action:     query.Action,
+		Limit:      query.Limit,
+	}
+	// validated as RFC 3339 by bindQuery
+	if query.From != "" {
+		filter.From, _ = time.Parse(time.RFC3339, query.From)
+	}
+	if query.To != "" {
+		filter.To, _ = time.Parse(time.RFC3339, query.To)
+	}
+
+	entries, err := h.auditService.ListAuditLogs(c.UserContext(), filter)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, entries)
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"event-booking-be/internal/realtime"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/utils"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AvailabilityHandler struct {
+	availabilityService service.AvailabilityService
+	heartbeatInterval   time.Duration
+}
+
+func NewAvailabilityHandler(availabilityService service.AvailabilityService, heartbeatInterval time.Duration) *AvailabilityHandler {
+	return &AvailabilityHandler{
+		availabilityService: availabilityService,
+		heartbeatInterval:   heartbeatInterval,
+	}
+}
+
+// StreamAvailability streams "X tickets left" for an event as Server-Sent
+// Events: the current value first, then every change, with comment lines as
+// heartbeats so proxies keep the connection open.
+func (h *AvailabilityHandler) StreamAvailability(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.EVENT_INVALID_ID, "Invalid event ID")
+	}
+
+	current, err := h.availabilityService.GetAvailability(c.UserContext(), id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	sub, err := h.availabilityService.Subscribe(id)
+	if err != nil {
+		if errors.Is(err, realtime.ErrTooManySubscribers) {
+			c.Set(fiber.HeaderRetryAfter, "30")
+		}
+		return ErrorResponse(c, fiber.StatusServiceUnavailable, utils.STREAM_UNAVAILABLE, "Live updates are unavailable, please retry later")
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	heartbeatInterval := h.heartbeatInterval
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		fmt.Fprint(w, "retry: 3000\n\n")
+		if writeAvailability(w, *current) != nil {
+			return
+		}
+
+		for {
+			select {
+			case update, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if writeAvailability(w, update) != nil {
+					return
+				}
+			case <-ticker.C:
+				// a failed flush means the client went away
+				fmt.Fprint(w, ": heartbeat\n\n")
+				if w.Flush() != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
+func writeAvailability(w *bufio.Writer, availability realtime.Availability) error {
+	data, err := json.Marshal(availability)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "id: %d\nevent: availability\ndata: %s\n\n", availability.UpdatedAt.UnixNano(), data)
+	return w.Flush()
+}
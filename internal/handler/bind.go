@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// bind parses the request body into out and validates it.
+func bind(c *fiber.Ctx, out interface{}) error {
+	if err := c.BodyParser(out); err != nil {
+		return apperrors.ErrInvalidBody
+	}
+	return validation.Struct(out)
+}
+
+// bindQuery parses the query string into out and validates it.
+func bindQuery(c *fiber.Ctx, out interface{}) error {
+	if err := c.QueryParser(out); err != nil {
+		return apperrors.ErrInvalidQuery
+	}
+	return validation.Struct(out)
+}
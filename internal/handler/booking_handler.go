@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"bufio"
+	"event-booking-be/internal/export"
 	"event-booking-be/internal/models"
 	"event-booking-be/internal/service"
 	"event-booking-be/internal/utils"
+	"fmt"
+	"log/slog"
 	"strconv"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -24,82 +27,158 @@ func (h *BookingHandler) CreateBooking(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(int)
 
 	var req models.CreateBookingRequest
-	if err := c.BodyParser(&req); err != nil {
-		return BadRequestResponse(c, utils.INVALID_REQUEST_BODY, "Invalid request body")
+	if err := bind(c, &req); err != nil {
+		return errorResponse(c, err)
 	}
 
-	booking, err := h.bookingService.CreateBooking(c.Context(), userID, &req)
+	booking, err := h.bookingService.CreateBooking(c.UserContext(), userID, &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "not enough tickets") {
-			return BadRequestResponse(c, utils.BOOKING_NOT_ENOUGH_TICKETS, err.Error())
-		}
-		return BadRequestResponse(c, utils.BOOKING_CREATE_FAILED, err.Error())
+		return errorResponse(c, err)
 	}
 
 	return CreatedResponse(c, booking)
 }
 
 func (h *BookingHandler) GetBooking(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		return BadRequestResponse(c, utils.BOOKING_INVALID_ID, "Invalid booking ID")
 	}
 
-	booking, err := h.bookingService.GetBooking(c.Context(), id)
+	booking, err := h.bookingService.GetBooking(c.UserContext(), userID, id)
 	if err != nil {
-		return NotFoundResponse(c, utils.BOOKING_NOT_FOUND, "Booking not found")
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, booking)
 }
 
+func (h *BookingHandler) GetBookingHistory(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.BOOKING_INVALID_ID, "Invalid booking ID")
+	}
+
+	history, err := h.bookingService.GetBookingHistory(c.UserContext(), userID, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, history)
+}
+
 func (h *BookingHandler) GetUserBookings(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(int)
 
-	bookings, err := h.bookingService.GetUserBookings(c.Context(), userID)
+	bookings, err := h.bookingService.GetUserBookings(c.UserContext(), userID)
 	if err != nil {
-		return InternalErrorResponse(c, utils.INTERNAL_SERVER_ERROR, err.Error())
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, bookings)
 }
 
 func (h *BookingHandler) ConfirmPayment(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		return BadRequestResponse(c, utils.BOOKING_INVALID_ID, "Invalid booking ID")
 	}
 
-	if err := h.bookingService.ConfirmPayment(c.Context(), id); err != nil {
-		// Check error type
-		if strings.Contains(err.Error(), "expired") {
-			return BadRequestResponse(c, utils.BOOKING_EXPIRED, err.Error())
-		}
-		if strings.Contains(err.Error(), "not in pending") {
-			return BadRequestResponse(c, utils.BOOKING_ALREADY_CONFIRMED, err.Error())
-		}
-		return BadRequestResponse(c, utils.BOOKING_NOT_FOUND, err.Error())
+	if err := h.bookingService.ConfirmPayment(c.UserContext(), userID, id); err != nil {
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, fiber.Map{"message": "Payment confirmed"})
 }
 
+func (h *BookingHandler) CheckIn(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.BOOKING_INVALID_ID, "Invalid booking ID")
+	}
+
+	booking, err := h.bookingService.CheckIn(c.UserContext(), userID, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, booking)
+}
+
 func (h *BookingHandler) CancelBooking(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		return BadRequestResponse(c, utils.BOOKING_INVALID_ID, "Invalid booking ID")
 	}
 
-	if err := h.bookingService.CancelBooking(c.Context(), id); err != nil {
-		// Check error type
-		if strings.Contains(err.Error(), "already cancelled") {
-			return BadRequestResponse(c, utils.BOOKING_ALREADY_CANCELLED, err.Error())
-		}
-		if strings.Contains(err.Error(), "confirmed") {
-			return BadRequestResponse(c, utils.BOOKING_ALREADY_CONFIRMED, "Cannot cancel confirmed booking")
-		}
-		return BadRequestResponse(c, utils.BOOKING_CANCEL_FAILED, err.Error())
+	if err := h.bookingService.CancelBooking(c.UserContext(), userID, id); err != nil {
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, fiber.Map{"message": "Booking cancelled"})
 }
+
+// ExportAttendees streams an event's confirmed attendees as a CSV or XLSX
+// download. Rows go out as they are read, so errors that happen mid-stream
+// can only be logged: the status line has already been sent.
+func (h *BookingHandler) ExportAttendees(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.EVENT_INVALID_ID, "Invalid event ID")
+	}
+
+	var query models.AttendeeExportQuery
+	if err := bindQuery(c, &query); err != nil {
+		return errorResponse(c, err)
+	}
+	format := query.Format
+	if format == "" {
+		format = export.FormatCSV
+	}
+	columns, err := export.SelectColumns(query.Columns)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	stream, err := h.bookingService.ExportAttendees(c.UserContext(), userID, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, export.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="event-%d-attendees.%s"`, id, format))
+
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		err := func() error {
+			writer, err := export.NewWriter(w, format, columns)
+			if err != nil {
+				return err
+			}
+			if err := stream(ctx, writer.Write); err != nil {
+				return err
+			}
+			if err := writer.Close(); err != nil {
+				return err
+			}
+			return w.Flush()
+		}()
+		if err != nil {
+			slog.ErrorContext(ctx, "Attendee export failed", "event_id", id, "error", err)
+		}
+	})
+
+	return nil
+}
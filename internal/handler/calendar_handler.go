@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"event-booking-be/internal/ical"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/utils"
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+func NewCalendarHandler(calendarService service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+func (h *CalendarHandler) GetEventCalendar(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.EVENT_INVALID_ID, "Invalid event ID")
+	}
+
+	entry, err := h.calendarService.GetEventEntry(c.UserContext(), id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return calendarResponse(c, fmt.Sprintf("event-%d.ics", id), entry.Summary, []*models.CalendarEntry{entry})
+}
+
+func (h *CalendarHandler) GetUpcomingCalendar(c *fiber.Ctx) error {
+	entries, err := h.calendarService.GetUpcomingEntries(c.UserContext())
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return calendarResponse(c, "events.ics", "Upcoming events", entries)
+}
+
+// GetUserCalendar serves a user's private feed. The token in the URL is the
+// only credential, as calendar apps can't send an auth header.
+func (h *CalendarHandler) GetUserCalendar(c *fiber.Ctx) error {
+	entries, err := h.calendarService.GetUserEntries(c.UserContext(), c.Params("token"))
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return calendarResponse(c, "bookings.ics", "My bookings", entries)
+}
+
+func (h *CalendarHandler) IssueToken(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	token, err := h.calendarService.IssueToken(c.UserContext(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return CreatedResponse(c, token)
+}
+
+func calendarResponse(c *fiber.Ctx, filename, name string, entries []*models.CalendarEntry) error {
+	c.Set(fiber.HeaderContentType, ical.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, filename))
+	return ical.Write(c.Response().BodyWriter(), name, entries)
+}
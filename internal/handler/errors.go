@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/utils"
+	"event-booking-be/internal/validation"
+	"log/slog"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	fiberutils "github.com/gofiber/fiber/v2/utils"
+)
+
+var kindStatus = map[apperrors.Kind]int{
+	apperrors.KindInvalid:      fiber.StatusBadRequest,
+	apperrors.KindUnauthorized: fiber.StatusUnauthorized,
+	apperrors.KindForbidden:    fiber.StatusForbidden,
+	apperrors.KindNotFound:     fiber.StatusNotFound,
+	apperrors.KindConflict:     fiber.StatusConflict,
+}
+
+// errorStatus maps err to an HTTP status, an error code and a message that is
+// safe to return. Errors that are neither domain errors nor fiber errors are
+// internal: their details stay in the logs.
+func errorStatus(err error) (int, string, string) {
+	var domainErr *apperrors.Error
+	if errors.As(err, &domainErr) {
+		if status, ok := kindStatus[domainErr.Kind]; ok {
+			return status, domainErr.Code, domainErr.Message
+		}
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		code := strings.ToUpper(strings.ReplaceAll(fiberutils.StatusMessage(fiberErr.Code), " ", "_"))
+		return fiberErr.Code, code, fiberErr.Message
+	}
+
+	return fiber.StatusInternalServerError, utils.INTERNAL_SERVER_ERROR, "Internal Server Error"
+}
+
+// errorResponse writes the response for an error returned by a service.
+// Validation errors list every failed field under details.
+func errorResponse(c *fiber.Ctx, err error) error {
+	var invalid validation.Errors
+	if errors.As(err, &invalid) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(Response{
+			Success: false,
+			Code:    utils.VALIDATION_FAILED,
+			Error:   "Validation failed",
+			Details: invalid,
+		})
+	}
+
+	status, code, message := errorStatus(err)
+	if status >= fiber.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), "Request failed", "error", err)
+	}
+	return ErrorResponse(c, status, code, message)
+}
+
+// ErrorHandler is the app-wide fiber error handler, for errors that escape
+// the handlers (unknown routes, oversized bodies, recovered panics). The
+// request logger has already logged them.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status, code, message := errorStatus(err)
+	return ErrorResponse(c, status, code, message)
+}
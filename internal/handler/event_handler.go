@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"bytes"
+	"event-booking-be/internal/importer"
 	"event-booking-be/internal/models"
 	"event-booking-be/internal/service"
 	"event-booking-be/internal/utils"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,14 +23,16 @@ func NewEventHandler(eventService service.EventService) *EventHandler {
 }
 
 func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
 	var req models.CreateEventRequest
-	if err := c.BodyParser(&req); err != nil {
-		return BadRequestResponse(c, utils.INVALID_REQUEST_BODY, "Invalid request body")
+	if err := bind(c, &req); err != nil {
+		return errorResponse(c, err)
 	}
 
-	event, err := h.eventService.CreateEvent(c.Context(), &req)
+	event, err := h.eventService.CreateEvent(c.UserContext(), userID, &req)
 	if err != nil {
-		return InternalErrorResponse(c, utils.EVENT_CREATE_FAILED, err.Error())
+		return errorResponse(c, err)
 	}
 
 	return CreatedResponse(c, event)
@@ -39,50 +44,52 @@ func (h *EventHandler) GetEvent(c *fiber.Ctx) error {
 		return BadRequestResponse(c, utils.EVENT_INVALID_ID, "Invalid event ID")
 	}
 
-	event, err := h.eventService.GetEvent(c.Context(), id)
+	event, err := h.eventService.GetEvent(c.UserContext(), id)
 	if err != nil {
-		return NotFoundResponse(c, utils.EVENT_NOT_FOUND, "Event not found")
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, event)
 }
 
 func (h *EventHandler) GetAllEvents(c *fiber.Ctx) error {
-	events, err := h.eventService.GetAllEvents(c.Context())
+	events, err := h.eventService.GetAllEvents(c.UserContext())
 	if err != nil {
-		return InternalErrorResponse(c, utils.INTERNAL_SERVER_ERROR, err.Error())
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, events)
 }
 
 func (h *EventHandler) UpdateEvent(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		return BadRequestResponse(c, utils.EVENT_INVALID_ID, "Invalid event ID")
 	}
 
 	var req models.UpdateEventRequest
-	if err := c.BodyParser(&req); err != nil {
-		return BadRequestResponse(c, utils.INVALID_REQUEST_BODY, "Invalid request body")
+	if err := bind(c, &req); err != nil {
+		return errorResponse(c, err)
 	}
 
-	event, err := h.eventService.UpdateEvent(c.Context(), id, &req)
+	event, err := h.eventService.UpdateEvent(c.UserContext(), userID, id, &req)
 	if err != nil {
-		return InternalErrorResponse(c, utils.EVENT_UPDATE_FAILED, err.Error())
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, event)
 }
 
 func (h *EventHandler) DeleteEvent(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
 		return BadRequestResponse(c, utils.EVENT_INVALID_ID, "Invalid event ID")
 	}
 
-	if err := h.eventService.DeleteEvent(c.Context(), id); err != nil {
-		return InternalErrorResponse(c, utils.EVENT_DELETE_FAILED, err.Error())
+	if err := h.eventService.DeleteEvent(c.UserContext(), userID, id); err != nil {
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, fiber.Map{"message": "Event deleted"})
@@ -94,10 +101,44 @@ func (h *EventHandler) GetEventStatistics(c *fiber.Ctx) error {
 		return BadRequestResponse(c, utils.EVENT_INVALID_ID, "Invalid event ID")
 	}
 
-	stats, err := h.eventService.GetEventStatistics(c.Context(), id)
+	stats, err := h.eventService.GetEventStatistics(c.UserContext(), id)
 	if err != nil {
-		return InternalErrorResponse(c, utils.EVENT_NOT_FOUND, err.Error())
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, stats)
 }
+
+// ImportEvents creates or updates the caller's events from a CSV or JSON
+// file sent as the request body. Problems with individual rows are reported
+// in the result, not as an error status.
+func (h *EventHandler) ImportEvents(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	var query models.EventImportQuery
+	if err := bindQuery(c, &query); err != nil {
+		return errorResponse(c, err)
+	}
+	format := query.Format
+	if format == "" {
+		format = importer.FormatJSON
+		if strings.HasPrefix(c.Get(fiber.HeaderContentType), "text/csv") {
+			format = importer.FormatCSV
+		}
+	}
+
+	rows, err := importer.ReadEvents(bytes.NewReader(c.Body()), format)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	result, err := h.eventService.ImportEvents(c.UserContext(), userID, rows, models.EventImportOptions{
+		Mode:   query.Mode,
+		DryRun: query.DryRun,
+	})
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, result)
+}
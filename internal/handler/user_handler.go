@@ -3,8 +3,6 @@ package handler
 import (
 	"event-booking-be/internal/models"
 	"event-booking-be/internal/service"
-	"event-booking-be/internal/utils"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -21,16 +19,13 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 
 func (h *UserHandler) Register(c *fiber.Ctx) error {
 	var req models.CreateUserRequest
-	if err := c.BodyParser(&req); err != nil {
-		return BadRequestResponse(c, utils.INVALID_REQUEST_BODY, "Invalid request body")
+	if err := bind(c, &req); err != nil {
+		return errorResponse(c, err)
 	}
 
-	user, err := h.userService.CreateUser(c.Context(), &req)
+	user, err := h.userService.CreateUser(c.UserContext(), &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "already exists") {
-			return BadRequestResponse(c, utils.USER_ALREADY_EXISTS, "Email already exists")
-		}
-		return InternalErrorResponse(c, utils.INTERNAL_SERVER_ERROR, err.Error())
+		return errorResponse(c, err)
 	}
 
 	return CreatedResponse(c, user)
@@ -38,13 +33,13 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 
 func (h *UserHandler) Login(c *fiber.Ctx) error {
 	var req models.LoginRequest
-	if err := c.BodyParser(&req); err != nil {
-		return BadRequestResponse(c, utils.INVALID_REQUEST_BODY, "Invalid request body")
+	if err := bind(c, &req); err != nil {
+		return errorResponse(c, err)
 	}
 
-	user, err := h.userService.Login(c.Context(), &req)
+	user, err := h.userService.Login(c.UserContext(), &req)
 	if err != nil {
-		return BadRequestResponse(c, utils.USER_INVALID_CREDENTIALS, "Invalid credentials")
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, fiber.Map{
@@ -56,9 +51,9 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 func (h *UserHandler) GetProfile(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(int)
 
-	user, err := h.userService.GetUser(c.Context(), userID)
+	user, err := h.userService.GetUser(c.UserContext(), userID)
 	if err != nil {
-		return NotFoundResponse(c, utils.USER_NOT_FOUND, "User not found")
+		return errorResponse(c, err)
 	}
 
 	return SuccessResponse(c, user)
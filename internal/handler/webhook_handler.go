@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/utils"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) CreateSubscription(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	var req models.CreateWebhookRequest
+	if err := bind(c, &req); err != nil {
+		return errorResponse(c, err)
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(c.UserContext(), userID, &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return CreatedResponse(c, &models.CreatedWebhookSubscription{
+		WebhookSubscription: *subscription,
+		Secret:              subscription.Secret,
+	})
+}
+
+func (h *WebhookHandler) GetSubscriptions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	subscriptions, err := h.webhookService.GetSubscriptions(c.UserContext(), userID)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, subscriptions)
+}
+
+func (h *WebhookHandler) GetSubscription(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.WEBHOOK_INVALID_ID, "Invalid webhook ID")
+	}
+
+	subscription, err := h.webhookService.GetSubscription(c.UserContext(), userID, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, subscription)
+}
+
+func (h *WebhookHandler) UpdateSubscription(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.WEBHOOK_INVALID_ID, "Invalid webhook ID")
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := bind(c, &req); err != nil {
+		return errorResponse(c, err)
+	}
+
+	subscription, err := h.webhookService.UpdateSubscription(c.UserContext(), userID, id, &req)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, subscription)
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.WEBHOOK_INVALID_ID, "Invalid webhook ID")
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.UserContext(), userID, id); err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, fiber.Map{"message": "Webhook deleted"})
+}
+
+func (h *WebhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.WEBHOOK_INVALID_ID, "Invalid webhook ID")
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(c.UserContext(), userID, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, deliveries)
+}
+
+func (h *WebhookHandler) SendTestEvent(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(int)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return BadRequestResponse(c, utils.WEBHOOK_INVALID_ID, "Invalid webhook ID")
+	}
+
+	delivery, err := h.webhookService.SendTestEvent(c.UserContext(), userID, id)
+	if err != nil {
+		return errorResponse(c, err)
+	}
+
+	return SuccessResponse(c, delivery)
+}
@@ -0,0 +1,103 @@
+// Package ical writes iCalendar (RFC 5545) feeds.
+package ical
+
+import (
+	"bufio"
+	"event-booking-be/internal/models"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	prodID = "-//event-booking-be//Events//EN"
+	// lines longer than this many octets are folded
+	maxLineOctets = 75
+)
+
+// Write writes a calendar named name holding entries. Times are written in
+// UTC, which every client converts to its own timezone.
+func Write(w io.Writer, name string, entries []*models.CalendarEntry) error {
+	cw := &writer{w: bufio.NewWriter(w)}
+	now := time.Now()
+
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", prodID)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	cw.line("X-WR-CALNAME", escape(name))
+
+	for _, entry := range entries {
+		status := "CONFIRMED"
+		if entry.Cancelled {
+			status = "CANCELLED"
+		}
+
+		cw.line("BEGIN", "VEVENT")
+		cw.line("UID", escape(entry.UID))
+		cw.line("SEQUENCE", fmt.Sprint(entry.Sequence))
+		cw.line("DTSTAMP", timestamp(now))
+		cw.line("DTSTART", timestamp(entry.Start))
+		cw.line("SUMMARY", escape(entry.Summary))
+		if entry.Description != "" {
+			cw.line("DESCRIPTION", escape(entry.Description))
+		}
+		cw.line("STATUS", status)
+		if !entry.UpdatedAt.IsZero() {
+			cw.line("LAST-MODIFIED", timestamp(entry.UpdatedAt))
+		}
+		cw.line("END", "VEVENT")
+	}
+
+	cw.line("END", "VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escape makes s safe as a TEXT value.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+type writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a content line, folded so no line is longer than 75 octets and
+// ended with CRLF, as RFC 5545 requires.
+func (cw *writer) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+
+	content := name + ":" + value
+	limit := maxLineOctets
+	for len(content) > limit {
+		// never split a UTF-8 sequence
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		if _, cw.err = cw.w.WriteString(content[:cut] + "\r\n "); cw.err != nil {
+			return
+		}
+		content = content[cut:]
+		// continuation lines start with a space, which counts
+		limit = maxLineOctets - 1
+	}
+	_, cw.err = cw.w.WriteString(content + "\r\n")
+}
@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/validation"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns maps each header a CSV file may use, the JSON field names, to
+// the setter for its values. Empty cells leave the field at its zero value.
+var csvColumns = map[string]func(row *models.EventImportRow, value string) error{
+	"external_ref": func(row *models.EventImportRow, value string) error { row.ExternalRef = value; return nil },
+	"name":         func(row *models.EventImportRow, value string) error { row.Name = value; return nil },
+	"description":  func(row *models.EventImportRow, value string) error { row.Description = value; return nil },
+	"date_time": func(row *models.EventImportRow, value string) (err error) {
+		row.DateTime, err = time.Parse(time.RFC3339, value)
+		return err
+	},
+	"total_tickets": intColumn(func(row *models.EventImportRow) *int { return &row.TotalTickets }),
+	"ticket_price": func(row *models.EventImportRow, value string) (err error) {
+		row.TicketPrice, err = strconv.ParseFloat(value, 64)
+		return err
+	},
+	"max_tickets_per_order":              intColumn(func(row *models.EventImportRow) *int { return &row.MaxTicketsPerOrder }),
+	"max_tickets_per_user":               intColumn(func(row *models.EventImportRow) *int { return &row.MaxTicketsPerUser }),
+	"max_tickets_per_payment_instrument": intColumn(func(row *models.EventImportRow) *int { return &row.MaxTicketsPerPaymentInstrument }),
+	"max_tickets_per_email_domain":       intColumn(func(row *models.EventImportRow) *int { return &row.MaxTicketsPerEmailDomain }),
+}
+
+func intColumn(field func(row *models.EventImportRow) *int) func(row *models.EventImportRow, value string) error {
+	return func(row *models.EventImportRow, value string) (err error) {
+		*field(row), err = strconv.Atoi(value)
+		return err
+	}
+}
+
+// utf8BOM starts CSV files saved by Excel.
+const utf8BOM = "\uFEFF"
+
+// readCSV reads a CSV file whose first line names its columns.
+func readCSV(r io.Reader) ([]*models.EventImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, apperrors.ErrInvalidImportFile.Withf("the file is empty")
+	}
+	if err != nil {
+		return nil, apperrors.ErrInvalidImportFile.Withf("can't read the header: %v", err)
+	}
+
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, utf8BOM)))
+		if _, ok := csvColumns[column]; !ok {
+			return nil, apperrors.ErrInvalidImportFile.Withf("unknown column %q", column)
+		}
+		if seen[column] {
+			return nil, apperrors.ErrInvalidImportFile.Withf("column %q appears twice", column)
+		}
+		seen[column] = true
+		header[i] = column
+	}
+
+	var rows []*models.EventImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, apperrors.ErrInvalidImportFile.Withf("%v", err)
+		}
+		if len(rows) == MaxRows {
+			return nil, errTooManyRows
+		}
+
+		row := &models.EventImportRow{}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if err := csvColumns[header[i]](row, value); err != nil {
+				row.ParseErrors = append(row.ParseErrors, csvParseError(header[i], value))
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func csvParseError(column, value string) validation.FieldError {
+	if column == "date_time" {
+		return parseError(column, "INVALID_DATETIME", "must be a timestamp formatted as "+time.RFC3339)
+	}
+	return parseError(column, "INVALID_NUMBER", "must be a number, not "+strconv.Quote(value))
+}
@@ -0,0 +1,51 @@
+// Package importer reads event import files. Every row is read even when
+// some are malformed, so a bad value is reported against its row instead of
+// rejecting the whole file; only a file that can't be read at all, or has
+// too many rows, is an error.
+package importer
+
+import (
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/validation"
+	"fmt"
+	"io"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// MaxRows bounds one import, keeping its transaction short.
+const MaxRows = 1000
+
+// ReadEvents reads the rows of an import file in format.
+func ReadEvents(r io.Reader, format string) ([]*models.EventImportRow, error) {
+	var (
+		rows []*models.EventImportRow
+		err  error
+	)
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(r)
+	case FormatJSON:
+		rows, err = readJSON(r)
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, apperrors.ErrInvalidImportFile.Withf("the file has no events")
+	}
+	return rows, nil
+}
+
+var errTooManyRows = apperrors.ErrInvalidImportFile.Withf("the file has more than %d events", MaxRows)
+
+func parseError(field, code, message string) validation.FieldError {
+	return validation.FieldError{Field: field, Code: code, Message: message}
+}
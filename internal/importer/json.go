@@ -0,0 +1,52 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/validation"
+	"io"
+	"strings"
+	"time"
+)
+
+// readJSON reads a JSON array of events. Each element is decoded on its own,
+// so a value of the wrong type fails only its row.
+func readJSON(r io.Reader) ([]*models.EventImportRow, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elements); err != nil {
+		return nil, apperrors.ErrInvalidImportFile.Withf("expected a JSON array of events: %v", err)
+	}
+	if len(elements) > MaxRows {
+		return nil, errTooManyRows
+	}
+
+	rows := make([]*models.EventImportRow, len(elements))
+	for i, element := range elements {
+		row := &models.EventImportRow{}
+		dec := json.NewDecoder(bytes.NewReader(element))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(row); err != nil {
+			row.ParseErrors = append(row.ParseErrors, jsonParseError(err))
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+func jsonParseError(err error) validation.FieldError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return parseError(typeErr.Field, "INVALID_TYPE", "must be a "+typeErr.Type.String()+", not a "+typeErr.Value)
+	}
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		return parseError("date_time", "INVALID_DATETIME", "must be a timestamp formatted as "+time.RFC3339)
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return parseError(strings.Trim(field, `"`), "UNKNOWN_FIELD", "is not an event field")
+	}
+	return parseError("", "INVALID_JSON", "must be a JSON object")
+}
@@ -0,0 +1,183 @@
+package jobqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"event-booking-be/internal/metrics"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/tracing"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Handler runs one job. Returning an error schedules a retry with backoff
+// until the job's MaxAttempts is used up. Handlers must be idempotent: a job
+// whose worker dies after the handler finished is run again once its lock
+// lapses.
+type Handler func(ctx context.Context, job *models.Job) error
+
+type Config struct {
+	PollInterval time.Duration
+	Visibility   time.Duration
+	BatchSize    int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Logger       *slog.Logger
+}
+
+// Worker claims due jobs from the jobs table and runs the handler registered
+// for their type. Any number of workers, on any number of instances, can run
+// against the same table.
+type Worker struct {
+	jobRepo  repository.JobRepository
+	id       string
+	handlers map[string]Handler
+	cfg      Config
+}
+
+func NewWorker(jobRepo repository.JobRepository, cfg Config) *Worker {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.Visibility <= 0 {
+		cfg.Visibility = time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 5 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Minute
+	}
+
+	return &Worker{
+		jobRepo:  jobRepo,
+		id:       workerID(),
+		handlers: make(map[string]Handler),
+		cfg:      cfg,
+	}
+}
+
+func (w *Worker) ID() string {
+	return w.id
+}
+
+func (w *Worker) Handle(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	w.cfg.Logger.InfoContext(ctx, "Job worker started", "worker_id", w.id)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if backlog, err := w.jobRepo.CountRunnable(ctx); err == nil {
+				metrics.SetJobBacklog(backlog)
+			}
+
+			for ctx.Err() == nil {
+				processed, err := w.ProcessDue(ctx)
+				if err != nil {
+					w.cfg.Logger.ErrorContext(ctx, "Error processing jobs", "error", err)
+				}
+				// a full batch means there's probably more waiting
+				if err != nil || processed < w.cfg.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// ProcessDue claims and runs one batch of due jobs and returns how many it ran.
+func (w *Worker) ProcessDue(ctx context.Context) (int, error) {
+	jobs, err := w.jobRepo.GetRunnable(ctx, w.cfg.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("can't fetch jobs: %w", err)
+	}
+
+	processed := 0
+	for _, job := range jobs {
+		claimed, err := w.jobRepo.Claim(ctx, job, w.id, w.cfg.Visibility)
+		if err != nil {
+			return processed, fmt.Errorf("failed to claim job %d: %w", job.ID, err)
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := w.run(ctx, job); err != nil {
+			w.cfg.Logger.ErrorContext(ctx, "Failed to record job outcome", "job_id", job.ID, "error", err)
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+func (w *Worker) run(ctx context.Context, job *models.Job) error {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return w.jobRepo.Fail(ctx, job, fmt.Sprintf("no handler for job type %q", job.Type))
+	}
+
+	// The handler joins the trace of the request that scheduled the job.
+	spanCtx, span := tracing.Start(tracing.WithTraceParent(ctx, job.TraceParent), "job "+job.Type,
+		attribute.Int64("job.id", job.ID), attribute.Int("job.attempt", job.Attempts))
+
+	start := time.Now()
+	handlerErr := handler(spanCtx, job)
+	tracing.End(span, handlerErr)
+	if handlerErr == nil {
+		metrics.ObserveJob(job.Type, "done", time.Since(start))
+		return w.jobRepo.Complete(ctx, job)
+	}
+	metrics.ObserveJob(job.Type, "error", time.Since(start))
+
+	w.cfg.Logger.WarnContext(spanCtx, "Job failed",
+		"job_id", job.ID, "job_type", job.Type, "attempt", job.Attempts, "error", handlerErr)
+
+	if job.Attempts >= job.MaxAttempts {
+		return w.jobRepo.Fail(ctx, job, handlerErr.Error())
+	}
+	return w.jobRepo.Retry(ctx, job, time.Now().Add(w.backoff(job.Attempts)), handlerErr.Error())
+}
+
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < w.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.cfg.MaxBackoff {
+		delay = w.cfg.MaxBackoff
+	}
+	return delay
+}
+
+func workerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(buf))
+}
@@ -0,0 +1,227 @@
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// renewScript extends the lease only while we still hold it, so an instance
+// that stalled past its TTL can't extend a lease someone else now owns.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type Config struct {
+	Key           string
+	TTL           time.Duration
+	RenewInterval time.Duration
+	Logger        *slog.Logger
+}
+
+// Job is a singleton background task. It is started when this instance
+// becomes leader and must return promptly once ctx is cancelled, which
+// happens as soon as leadership is lost.
+type Job func(ctx context.Context)
+
+type registeredJob struct {
+	name string
+	run  Job
+}
+
+// Elector holds a Redis lease that marks one instance as leader. Only the
+// leader runs the registered jobs. If the leader dies without releasing the
+// lease, another instance takes over once the TTL lapses.
+type Elector struct {
+	client redis.Cmdable
+	id     string
+	cfg    Config
+
+	mu       sync.Mutex
+	jobs     []registeredJob
+	isLeader bool
+	cancel   context.CancelFunc
+	running  sync.WaitGroup
+	done     chan struct{}
+}
+
+func NewElector(client redis.Cmdable, cfg Config) *Elector {
+	if cfg.Key == "" {
+		cfg.Key = "event-booking:leader"
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 15 * time.Second
+	}
+	if cfg.RenewInterval <= 0 || cfg.RenewInterval >= cfg.TTL {
+		cfg.RenewInterval = cfg.TTL / 3
+	}
+
+	return &Elector{
+		client: client,
+		id:     instanceID(),
+		cfg:    cfg,
+		done:   make(chan struct{}),
+	}
+}
+
+func (e *Elector) ID() string {
+	return e.id
+}
+
+// Register adds a singleton job. Jobs must be registered before Run.
+func (e *Elector) Register(name string, job Job) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.jobs = append(e.jobs, registeredJob{name: name, run: job})
+}
+
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.isLeader
+}
+
+// Leader returns the ID of the current lease holder, or "" if there is none.
+func (e *Elector) Leader(ctx context.Context) (string, error) {
+	id, err := e.client.Get(ctx, e.cfg.Key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return id, err
+}
+
+// Run campaigns for leadership until ctx is cancelled, then stops the jobs
+// and releases the lease so another instance can take over right away.
+func (e *Elector) Run(ctx context.Context) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.cfg.RenewInterval)
+	defer ticker.Stop()
+
+	e.cfg.Logger.InfoContext(ctx, "Leader election started", "instance", e.id)
+
+	e.Step(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			e.Resign()
+			return
+		case <-ticker.C:
+			e.Step(ctx)
+		}
+	}
+}
+
+// Wait blocks until Run has returned and the lease has been released.
+func (e *Elector) Wait() {
+	<-e.done
+}
+
+// Step makes one attempt to acquire or renew the lease and starts or stops
+// the registered jobs to match. It reports whether this instance is leader.
+func (e *Elector) Step(ctx context.Context) bool {
+	var held bool
+	var err error
+	if e.IsLeader() {
+		var renewed int64
+		renewed, err = renewScript.Run(ctx, e.client, []string{e.cfg.Key}, e.id, e.cfg.TTL.Milliseconds()).Int64()
+		held = renewed == 1
+	} else {
+		held, err = e.client.SetNX(ctx, e.cfg.Key, e.id, e.cfg.TTL).Result()
+	}
+	if err != nil {
+		// we can't tell whether the lease is still ours; stepping down is the
+		// only safe answer since it may lapse before Redis is reachable again
+		e.cfg.Logger.ErrorContext(ctx, "Leader election error", "instance", e.id, "error", err)
+		held = false
+	}
+
+	switch {
+	case held && !e.IsLeader():
+		e.startJobs(ctx)
+	case !held && e.IsLeader():
+		e.cfg.Logger.WarnContext(ctx, "Lost leadership", "instance", e.id)
+		e.stopJobs()
+	}
+	return held
+}
+
+func (e *Elector) startJobs(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.cfg.Logger.InfoContext(ctx, "Became leader", "instance", e.id, "jobs", len(e.jobs))
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+	e.isLeader = true
+	for _, job := range e.jobs {
+		e.running.Add(1)
+		go func(job registeredJob) {
+			defer e.running.Done()
+			job.run(jobCtx)
+			e.cfg.Logger.InfoContext(ctx, "Singleton job stopped", "job", job.name)
+		}(job)
+	}
+}
+
+func (e *Elector) stopJobs() {
+	e.mu.Lock()
+	cancel := e.cancel
+	e.cancel = nil
+	e.isLeader = false
+	e.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	e.running.Wait()
+}
+
+// Resign stops the jobs and hands the lease back if this instance holds it.
+func (e *Elector) Resign() {
+	wasLeader := e.IsLeader()
+	e.stopJobs()
+	if !wasLeader {
+		return
+	}
+
+	// Run's ctx is already cancelled by the time it resigns
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := releaseScript.Run(ctx, e.client, []string{e.cfg.Key}, e.id).Err(); err != nil {
+		e.cfg.Logger.ErrorContext(ctx, "Error releasing leadership", "instance", e.id, "error", err)
+	}
+}
+
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "instance"
+	}
+
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(buf))
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger sends GORM's query log to logger: failed queries at error, queries
+// slower than slowThreshold at warn, and every other query at debug.
+func GormLogger(logger *slog.Logger, slowThreshold time.Duration) gormlogger.Interface {
+	return &gormLogger{logger: logger, slowThreshold: slowThreshold}
+}
+
+type gormLogger struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+}
+
+// LogMode is a no-op: the level is the slog logger's.
+func (l *gormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.InfoContext(ctx, msg, "args", args)
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.WarnContext(ctx, msg, "args", args)
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	l.logger.ErrorContext(ctx, msg, "args", args)
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+
+	level := slog.LevelDebug
+	msg := "Query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = slog.LevelError, "Query failed"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		level, msg = slog.LevelWarn, "Slow query"
+	}
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []any{"sql", sql, "rows", rows, "duration_ms", float64(elapsed.Microseconds()) / 1000}
+	if level == slog.LevelError {
+		attrs = append(attrs, "error", err)
+	}
+	l.logger.Log(ctx, level, msg, attrs...)
+}
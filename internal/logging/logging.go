@@ -0,0 +1,82 @@
+// Package logging sets up structured JSON logging. Fields attached to a
+// context with With (request ID, user, booking and event IDs) are added to
+// every line logged with that context, along with the active trace ID.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New returns a JSON logger writing to w at level and above.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(&contextHandler{Handler: slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// ParseLevel parses debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+	return level, nil
+}
+
+type fieldsKey struct{}
+
+// With returns ctx carrying args (alternating keys and values, or slog.Attr)
+// in addition to any fields ctx already carries. A key that is already set is
+// replaced rather than repeated.
+func With(ctx context.Context, args ...any) context.Context {
+	parent, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	record := slog.Record{}
+	record.Add(args...)
+
+	fields := make([]slog.Attr, len(parent), len(parent)+record.NumAttrs())
+	copy(fields, parent)
+	record.Attrs(func(attr slog.Attr) bool {
+		for i := range fields {
+			if fields[i].Key == attr.Key {
+				fields[i] = attr
+				return true
+			}
+		}
+		fields = append(fields, attr)
+		return true
+	})
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// Fields returns the fields attached to ctx with With.
+func Fields(ctx context.Context) []slog.Attr {
+	fields, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	return fields
+}
+
+// contextHandler adds the context's fields and trace ID to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		record.AddAttrs(Fields(ctx)...)
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			record.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+)
+
+const namespace = "event_booking"
+
+// Registry holds every collector the API exposes on /metrics. A dedicated
+// registry (rather than the global default) keeps tests free to create
+// several apps without duplicate-registration panics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	bookingsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bookings_total",
+		Help:      "Booking state changes by event and action (created, confirmed, cancelled, expired).",
+	}, []string{"event_id", "action"})
+
+	ticketsSoldTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tickets_sold_total",
+		Help:      "Tickets in confirmed bookings, by event.",
+	}, []string{"event_id"})
+
+	eventLockWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "event_lock_wait_seconds",
+		Help:      "Time spent waiting for the event row lock in LockForUpdate.",
+		Buckets:   []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	})
+
+	jobRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_run_duration_seconds",
+		Help:      "Background job handler duration by job type and outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type", "outcome"})
+
+	jobBacklog = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_backlog",
+		Help:      "Jobs that are due and waiting for a worker.",
+	})
+
+	expirySweepDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "expiry_sweep_duration_seconds",
+		Help:      "Duration of the expired-booking sweep.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	expirySweepBacklog = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "expiry_sweep_backlog",
+		Help:      "Pending bookings past their expiry found by the last sweep.",
+	})
+
+	rateLimitedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected with 429 by route group.",
+	}, []string{"group"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		bookingsTotal,
+		ticketsSoldTotal,
+		eventLockWait,
+		jobRunDuration,
+		jobBacklog,
+		expirySweepDuration,
+		expirySweepBacklog,
+		rateLimitedTotal,
+	)
+}
+
+// RegisterDB exposes the connection pool stats of db.
+func RegisterDB(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// RegisterRedis exposes the connection pool stats of client.
+func RegisterRedis(client *redis.Client) {
+	Registry.MustRegister(newRedisCollector(client))
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
+
+// Middleware records the latency of every request. Routes are labelled by
+// their template (/api/v1/bookings/:id), not the raw path, to keep the label
+// set bounded; requests that match no route are grouped as "unmatched".
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			} else {
+				status = fiber.StatusInternalServerError
+			}
+		}
+
+		route := c.Route().Path
+		if status == fiber.StatusNotFound && route == "/" && c.Path() != "/" {
+			route = "unmatched"
+		}
+
+		httpRequestDuration.
+			WithLabelValues(c.Method(), route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// RecordBooking counts a committed booking state change. Confirmations also
+// count their tickets as sold.
+func RecordBooking(action string, eventID, tickets int) {
+	id := strconv.Itoa(eventID)
+	bookingsTotal.WithLabelValues(id, action).Inc()
+	if action == "confirmed" {
+		ticketsSoldTotal.WithLabelValues(id).Add(float64(tickets))
+	}
+}
+
+func ObserveLockWait(d time.Duration) {
+	eventLockWait.Observe(d.Seconds())
+}
+
+func ObserveJob(jobType, outcome string, d time.Duration) {
+	jobRunDuration.WithLabelValues(jobType, outcome).Observe(d.Seconds())
+}
+
+func SetJobBacklog(n int64) {
+	jobBacklog.Set(float64(n))
+}
+
+func ObserveExpirySweep(backlog int, d time.Duration) {
+	expirySweepBacklog.Set(float64(backlog))
+	expirySweepDuration.Observe(d.Seconds())
+}
+
+func RecordRateLimited(group string) {
+	rateLimitedTotal.WithLabelValues(group).Inc()
+}
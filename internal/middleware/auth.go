@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"event-booking-be/internal/audit"
+	"event-booking-be/internal/logging"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -24,6 +26,8 @@ func AuthMiddleware() fiber.Handler {
 		}
 
 		c.Locals("userID", userID)
+		ctx := audit.WithActor(c.UserContext(), audit.User(userID))
+		c.SetUserContext(logging.With(ctx, "user_id", userID))
 		return c.Next()
 	}
 }
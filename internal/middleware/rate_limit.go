@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/metrics"
+	"event-booking-be/internal/ratelimit"
+	"event-booking-be/internal/utils"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimit allows limit requests per client to the routes of group. Clients
+// are identified by user ID when AuthMiddleware ran first, and by IP
+// otherwise. Every response carries RateLimit-Limit, -Remaining and -Reset;
+// rejected ones get 429 with Retry-After. If the store fails the request is
+// let through rather than taking the API down with it.
+func RateLimit(store ratelimit.Store, group string, limit ratelimit.Limit) fiber.Handler {
+	if limit.Unlimited() {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := group + ":ip:" + c.IP()
+		if userID, ok := c.Locals("userID").(int); ok {
+			key = group + ":user:" + strconv.Itoa(userID)
+		}
+
+		res, err := store.Allow(c.UserContext(), key, limit)
+		if err != nil {
+			slog.WarnContext(c.UserContext(), "Rate limit check failed", "group", group, "error", err)
+			return c.Next()
+		}
+
+		reset := strconv.Itoa(seconds(res.Reset))
+		c.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Set("RateLimit-Reset", reset)
+
+		if !res.Allowed {
+			metrics.RecordRateLimited(group)
+			c.Set(fiber.HeaderRetryAfter, reset)
+			return handler.ErrorResponse(c, fiber.StatusTooManyRequests, utils.RATE_LIMITED, "Too many requests, please retry later")
+		}
+		return c.Next()
+	}
+}
+
+// seconds rounds up, so clients that wait as told are not rejected again.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"event-booking-be/internal/audit"
+	"event-booking-be/internal/logging"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID reuses the caller's X-Request-ID, or generates one, echoes it in
+// the response and attaches it to the request context so every log line for
+// the request carries it, as does every audit log entry it causes.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(RequestIDHeader, id)
+		c.Locals("requestID", id)
+		ctx := audit.WithRequestID(c.UserContext(), id)
+		c.SetUserContext(logging.With(ctx, "request_id", id))
+		return c.Next()
+	}
+}
+
+// validRequestID accepts up to 128 printable ASCII characters, so a caller
+// cannot inject newlines or huge values into the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
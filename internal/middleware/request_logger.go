@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestLogger writes one line per request once it has been handled. Server
+// errors are logged at error, client errors at warn and the rest at info.
+func RequestLogger(logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if e, ok := err.(*fiber.Error); ok {
+			status = e.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"method", c.Method(),
+			"path", c.Path(),
+			"route", c.Route().Path,
+			"status", status,
+			"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"ip", c.IP(),
+		}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
+
+		// the context adds the request ID and, once AuthMiddleware has run,
+		// the user ID
+		logger.Log(c.UserContext(), level, "Request handled", attrs...)
+		return err
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/service"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole lets through only users with role. It must run after
+// AuthMiddleware; unknown users are refused like any other non-admin.
+func RequireRole(userService service.UserService, role string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("userID").(int)
+		if !ok {
+			return apperrors.ErrForbidden
+		}
+
+		user, err := userService.GetUser(c.UserContext(), userID)
+		if apperrors.IsNotFound(err) {
+			return apperrors.ErrForbidden
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check role: %w", err)
+		}
+		if user.Role != role {
+			return apperrors.ErrForbidden
+		}
+		return c.Next()
+	}
+}
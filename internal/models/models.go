@@ -1,6 +1,8 @@
 package models
 
 import (
+	"event-booking-be/internal/validation"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,33 +14,74 @@ const (
 	BookingStatusPending   BookingStatus = "PENDING"
 	BookingStatusConfirmed BookingStatus = "CONFIRMED"
 	BookingStatusCancelled BookingStatus = "CANCELLED"
+	BookingStatusExpired   BookingStatus = "EXPIRED"
 )
 
 type Event struct {
-	ID           int            `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name         string         `gorm:"type:varchar(255);not null" json:"name"`
-	Description  string         `gorm:"type:text" json:"description"`
-	DateTime     time.Time      `gorm:"not null" json:"date_time"`
-	TotalTickets int            `gorm:"not null" json:"total_tickets"`
-	TicketPrice  float64        `gorm:"type:decimal(10,2);not null" json:"ticket_price"`
-	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
-	Bookings     []Booking      `gorm:"foreignKey:EventID" json:"-"`
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description  string    `gorm:"type:text" json:"description"`
+	DateTime     time.Time `gorm:"not null" json:"date_time"`
+	TotalTickets int       `gorm:"not null" json:"total_tickets"` // tickets still available
+	Capacity     int       `gorm:"not null;default:0" json:"capacity"`
+	TicketPrice  float64   `gorm:"type:decimal(10,2);not null" json:"ticket_price"`
+	OrganizerID  *int      `gorm:"index" json:"organizer_id,omitempty"`
+	// ExternalRef is the organizer's own ID for the event, set by imports.
+	ExternalRef *string `gorm:"type:varchar(100)" json:"external_ref,omitempty"`
+	// Sequence counts the changes calendar apps show (name, description,
+	// date), so they replace their copy of the event.
+	Sequence int `gorm:"not null;default:0" json:"sequence"`
+	// Purchase limits; 0 means no limit. Held tickets are those in pending
+	// and confirmed bookings.
+	MaxTicketsPerOrder             int            `gorm:"not null;default:0" json:"max_tickets_per_order"`
+	MaxTicketsPerUser              int            `gorm:"not null;default:0" json:"max_tickets_per_user"`
+	MaxTicketsPerPaymentInstrument int            `gorm:"not null;default:0" json:"max_tickets_per_payment_instrument"`
+	MaxTicketsPerEmailDomain       int            `gorm:"not null;default:0" json:"max_tickets_per_email_domain"`
+	CreatedAt                      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt                      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt                      gorm.DeletedAt `gorm:"index" json:"-"`
+	Bookings                       []Booking      `gorm:"foreignKey:EventID" json:"-"`
+	// TicketTypes are the kinds of ticket on sale besides the default one at
+	// TicketPrice. They all draw on TotalTickets.
+	TicketTypes []TicketType `gorm:"foreignKey:EventID" json:"ticket_types,omitempty"`
 }
 
 func (Event) TableName() string {
 	return "events"
 }
 
+// TicketType is a kind of ticket an event sells at its own price, such as
+// "VIP". Bookings record the name, so renaming or removing a type leaves
+// existing bookings as they were.
+type TicketType struct {
+	ID      int     `gorm:"primaryKey;autoIncrement" json:"-"`
+	EventID int     `gorm:"not null;uniqueIndex:idx_ticket_types_event_name" json:"-"`
+	Name    string  `gorm:"type:varchar(50);not null;uniqueIndex:idx_ticket_types_event_name" json:"name"`
+	Price   float64 `gorm:"type:decimal(10,2);not null" json:"price"`
+}
+
+func (TicketType) TableName() string {
+	return "ticket_types"
+}
+
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
 type User struct {
 	ID        int            `gorm:"primaryKey;autoIncrement" json:"id"`
 	Name      string         `gorm:"type:varchar(255);not null" json:"name"`
 	Email     string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	Locale    string         `gorm:"type:varchar(10);not null;default:'en'" json:"locale"`
+	Role      string         `gorm:"type:varchar(20);not null;default:'user'" json:"role"`
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 	Bookings  []Booking      `gorm:"foreignKey:UserID" json:"-"`
+	// CalendarTokenHash is the SHA-256 of the token in the user's private
+	// calendar feed URL; the token itself is only shown when it is issued.
+	CalendarTokenHash *string `gorm:"type:varchar(64);uniqueIndex" json:"-"`
 }
 
 func (User) TableName() string {
@@ -46,42 +89,387 @@ func (User) TableName() string {
 }
 
 type Booking struct {
-	ID          int            `gorm:"primaryKey;autoIncrement" json:"id"`
-	UserID      int            `gorm:"not null;index" json:"user_id"`
-	EventID     int            `gorm:"not null;index" json:"event_id"`
-	TicketCount int            `gorm:"not null" json:"ticket_count"`
-	TotalPrice  float64        `gorm:"type:decimal(10,2);not null" json:"total_price"`
-	Status      BookingStatus  `gorm:"type:varchar(20);not null;index" json:"status"`
-	ExpiresAt   time.Time      `gorm:"not null;index" json:"expires_at"`
-	ConfirmedAt *time.Time     `json:"confirmed_at,omitempty"`
-	CancelledAt *time.Time     `json:"cancelled_at,omitempty"`
-	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
-	User        User           `gorm:"foreignKey:UserID" json:"-"`
-	Event       Event          `gorm:"foreignKey:EventID" json:"-"`
+	ID          int           `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      int           `gorm:"not null;index" json:"user_id"`
+	EventID     int           `gorm:"not null;index" json:"event_id"`
+	TicketCount int           `gorm:"not null" json:"ticket_count"`
+	TotalPrice  float64       `gorm:"type:decimal(10,2);not null" json:"total_price"`
+	Status      BookingStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	// PaymentInstrument is the payment provider's fingerprint of the card or
+	// account that will pay, used to enforce per-instrument limits.
+	PaymentInstrument string         `gorm:"type:varchar(255);index" json:"payment_instrument,omitempty"`
+	TicketType        string         `gorm:"type:varchar(50);not null;default:''" json:"ticket_type,omitempty"` // empty for the default ticket
+	ExpiresAt         time.Time      `gorm:"not null;index" json:"expires_at"`
+	ConfirmedAt       *time.Time     `json:"confirmed_at,omitempty"`
+	CancelledAt       *time.Time     `json:"cancelled_at,omitempty"`
+	ExpiredAt         *time.Time     `json:"expired_at,omitempty"`
+	CheckedInAt       *time.Time     `json:"checked_in_at,omitempty"` // when door staff let the holder in
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+	User              User           `gorm:"foreignKey:UserID" json:"-"`
+	Event             Event          `gorm:"foreignKey:EventID" json:"-"`
 }
 
 func (Booking) TableName() string {
 	return "bookings"
 }
 
+// Reasons recorded with booking status changes.
+const (
+	BookingReasonCreated          = "created"
+	BookingReasonPaymentConfirmed = "payment confirmed"
+	BookingReasonUserCancelled    = "user cancelled"
+	BookingReasonExpiredByWorker  = "expired by worker"
+	BookingReasonForceConfirmed   = "force-confirmed by operator"
+	BookingReasonForceExpired     = "force-expired by operator"
+)
+
+// BookingStatusChange is one entry of a booking's status history. The first
+// entry of every booking has no FromStatus: it is the booking's creation.
+type BookingStatusChange struct {
+	ID         int64         `gorm:"primaryKey;autoIncrement" json:"id"`
+	BookingID  int           `gorm:"not null;index" json:"booking_id"`
+	FromStatus BookingStatus `gorm:"type:varchar(20)" json:"from_status,omitempty"`
+	ToStatus   BookingStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	Reason     string        `gorm:"type:varchar(255);not null" json:"reason"`
+	ActorType  string        `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID    *int          `json:"actor_id,omitempty"`
+	ActorName  string        `gorm:"type:varchar(255)" json:"actor_name,omitempty"`
+	CreatedAt  time.Time     `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (BookingStatusChange) TableName() string {
+	return "booking_status_history"
+}
+
+// BookingReference is the human-facing code for a booking, as printed in
+// emails and on tickets.
+func BookingReference(bookingID int) string {
+	return fmt.Sprintf("BK-%06d", bookingID)
+}
+
+// Domain events published through the outbox.
+const (
+	AggregateTypeBooking = "booking"
+	AggregateTypeEvent   = "event"
+
+	DomainEventBookingCreated   = "booking.created"
+	DomainEventBookingConfirmed = "booking.confirmed"
+	DomainEventBookingCancelled = "booking.cancelled"
+	DomainEventBookingExpired   = "booking.expired"
+	DomainEventEventUpdated     = "event.updated"
+)
+
+// OutboxMessage is a domain event written in the same transaction as the state
+// change it describes, and relayed to external sinks afterwards.
+type OutboxMessage struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AggregateType string     `gorm:"type:varchar(50);not null;index:idx_outbox_aggregate" json:"aggregate_type"`
+	AggregateID   int        `gorm:"not null;index:idx_outbox_aggregate" json:"aggregate_id"`
+	EventType     string     `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	PublishedAt   *time.Time `gorm:"index" json:"published_at,omitempty"`
+	TraceParent   string     `gorm:"type:varchar(55)" json:"trace_parent,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookEventTest is only ever sent by the "send test event" endpoint.
+const WebhookEventTest = "webhook.test"
+
+// WebhookEventTypes lists the domain events organizers can subscribe to.
+var WebhookEventTypes = []string{
+	DomainEventBookingCreated,
+	DomainEventBookingConfirmed,
+	DomainEventBookingCancelled,
+	DomainEventBookingExpired,
+	DomainEventEventUpdated,
+}
+
+type WebhookSubscription struct {
+	ID                  int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrganizerID         int            `gorm:"not null;index" json:"organizer_id"`
+	URL                 string         `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret              string         `gorm:"type:varchar(255);not null" json:"-"`
+	EventTypes          string         `gorm:"type:text" json:"event_types"` // comma-separated, empty means all
+	Active              bool           `gorm:"not null;default:true" json:"active"`
+	ConsecutiveFailures int            `gorm:"not null;default:0" json:"consecutive_failures"`
+	DisabledAt          *time.Time     `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// CreatedWebhookSubscription is the reply to creating a subscription, the
+// only one that shows its signing secret.
+type CreatedWebhookSubscription struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+type WebhookDelivery struct {
+	ID              int64                 `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubscriptionID  int                   `gorm:"not null;uniqueIndex:idx_webhook_delivery_message" json:"subscription_id"`
+	OutboxMessageID *int64                `gorm:"uniqueIndex:idx_webhook_delivery_message" json:"outbox_message_id,omitempty"`
+	EventType       string                `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload         string                `gorm:"type:text;not null" json:"payload"`
+	Status          WebhookDeliveryStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Attempts        int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt   time.Time             `gorm:"not null;index" json:"next_attempt_at"`
+	ResponseStatus  int                   `json:"response_status,omitempty"`
+	ResponseBody    string                `gorm:"type:text" json:"response_body,omitempty"`
+	LastError       string                `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt     *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt       time.Time             `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+type ReminderStatus string
+
+const (
+	ReminderStatusPending   ReminderStatus = "PENDING"
+	ReminderStatusSending   ReminderStatus = "SENDING"
+	ReminderStatusSent      ReminderStatus = "SENT"
+	ReminderStatusCancelled ReminderStatus = "CANCELLED"
+	ReminderStatusFailed    ReminderStatus = "FAILED"
+)
+
+// Reminder is a scheduled "your event is coming up" email for one confirmed
+// booking, OffsetMinutes before the event starts.
+type Reminder struct {
+	ID            int            `gorm:"primaryKey;autoIncrement" json:"id"`
+	BookingID     int            `gorm:"not null;uniqueIndex:idx_reminder_booking_offset" json:"booking_id"`
+	OffsetMinutes int            `gorm:"not null;uniqueIndex:idx_reminder_booking_offset" json:"offset_minutes"`
+	EventID       int            `gorm:"not null;index" json:"event_id"`
+	SendAt        time.Time      `gorm:"not null;index" json:"send_at"`
+	Status        ReminderStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Attempts      int            `gorm:"not null;default:0" json:"attempts"`
+	ClaimedUntil  *time.Time     `json:"claimed_until,omitempty"`
+	SentAt        *time.Time     `json:"sent_at,omitempty"`
+	LastError     string         `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Reminder) TableName() string {
+	return "reminders"
+}
+
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "PENDING"
+	JobStatusRunning JobStatus = "RUNNING"
+	JobStatusDone    JobStatus = "DONE"
+	JobStatusFailed  JobStatus = "FAILED"
+)
+
+const (
+	JobTypeExpireBooking = "booking.expire"
+	JobTypeSendEmail     = "email.send"
+)
+
+// Job is a unit of background work that becomes runnable at RunAt. A worker
+// that claims it holds it until LockedUntil; if the worker dies the lock lapses
+// and another worker picks the job up.
+type Job struct {
+	ID          int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type        string     `gorm:"type:varchar(100);not null" json:"type"`
+	Payload     string     `gorm:"type:text;not null" json:"payload"`
+	UniqueKey   *string    `gorm:"type:varchar(255);uniqueIndex" json:"unique_key,omitempty"`
+	RunAt       time.Time  `gorm:"not null;index" json:"run_at"`
+	Status      JobStatus  `gorm:"type:varchar(20);not null;index" json:"status"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"not null;default:5" json:"max_attempts"`
+	LockedBy    string     `gorm:"type:varchar(255)" json:"locked_by,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	TraceParent string     `gorm:"type:varchar(55)" json:"trace_parent,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// Audit log actions, named <entity>.<what happened>.
+const (
+	AuditEntityEvent   = "event"
+	AuditEntityBooking = "booking"
+	AuditEntityUser    = "user"
+
+	AuditEventCreated            = "event.created"
+	AuditEventUpdated            = "event.updated"
+	AuditEventDeleted            = "event.deleted"
+	AuditEventInventoryRecounted = "event.inventory_recounted"
+	AuditBookingCreated          = "booking.created"
+	AuditBookingConfirmed        = "booking.confirmed"
+	AuditBookingCancelled        = "booking.cancelled"
+	AuditBookingExpired          = "booking.expired"
+	AuditBookingForceConfirmed   = "booking.force_confirmed"
+	AuditBookingForceExpired     = "booking.force_expired"
+	AuditBookingCheckedIn        = "booking.checked_in"
+	AuditUserCreated             = "user.created"
+	AuditUserPromoted            = "user.promoted"
+	AuditUserCalendarTokenIssued = "user.calendar_token_issued"
+)
+
+// AuditLog records one mutation: who made it, in which request, and the
+// fields it changed. Rows are only ever inserted.
+type AuditLog struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ActorType  string    `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID    *int      `gorm:"index" json:"actor_id,omitempty"`
+	ActorName  string    `gorm:"type:varchar(255)" json:"actor_name,omitempty"`
+	Action     string    `gorm:"type:varchar(100);not null;index" json:"action"`
+	EntityType string    `gorm:"type:varchar(50);not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID   int       `gorm:"not null;index:idx_audit_logs_entity" json:"entity_id"`
+	Changes    string    `gorm:"type:text;not null" json:"changes"` // JSON: field -> {before, after}
+	RequestID  string    `gorm:"type:varchar(128);index" json:"request_id,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+type AuditLogFilter struct {
+	EntityType string
+	EntityID   int
+	ActorID    int
+	Action     string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
 // DTOs
 
 type CreateEventRequest struct {
-	Name         string    `json:"name" validate:"required"`
+	Name         string    `json:"name" validate:"required,max=255"`
 	Description  string    `json:"description"`
-	DateTime     time.Time `json:"date_time" validate:"required"`
+	DateTime     time.Time `json:"date_time" validate:"required,future"`
 	TotalTickets int       `json:"total_tickets" validate:"required,min=1"`
-	TicketPrice  float64   `json:"ticket_price" validate:"required,min=0"`
+	TicketPrice  float64   `json:"ticket_price" validate:"min=0"`
+
+	MaxTicketsPerOrder             int `json:"max_tickets_per_order" validate:"min=0,max=10"`
+	MaxTicketsPerUser              int `json:"max_tickets_per_user" validate:"min=0"`
+	MaxTicketsPerPaymentInstrument int `json:"max_tickets_per_payment_instrument" validate:"min=0"`
+	MaxTicketsPerEmailDomain       int `json:"max_tickets_per_email_domain" validate:"min=0"`
+
+	TicketTypes []TicketTypeRequest `json:"ticket_types,omitempty" validate:"omitempty,max=20,unique=Name,dive"`
+}
+
+type TicketTypeRequest struct {
+	Name  string  `json:"name" validate:"required,max=50"`
+	Price float64 `json:"price" validate:"min=0"`
 }
 
 type UpdateEventRequest struct {
-	Name         *string    `json:"name,omitempty"`
+	Name         *string    `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
 	Description  *string    `json:"description,omitempty"`
-	DateTime     *time.Time `json:"date_time,omitempty"`
-	TotalTickets *int       `json:"total_tickets,omitempty"`
-	TicketPrice  *float64   `json:"ticket_price,omitempty"`
+	DateTime     *time.Time `json:"date_time,omitempty" validate:"omitempty,future"`
+	TotalTickets *int       `json:"total_tickets,omitempty" validate:"omitempty,min=1"`
+	TicketPrice  *float64   `json:"ticket_price,omitempty" validate:"omitempty,min=0"`
+
+	MaxTicketsPerOrder             *int `json:"max_tickets_per_order,omitempty" validate:"omitempty,min=0,max=10"`
+	MaxTicketsPerUser              *int `json:"max_tickets_per_user,omitempty" validate:"omitempty,min=0"`
+	MaxTicketsPerPaymentInstrument *int `json:"max_tickets_per_payment_instrument,omitempty" validate:"omitempty,min=0"`
+	MaxTicketsPerEmailDomain       *int `json:"max_tickets_per_email_domain,omitempty" validate:"omitempty,min=0"`
+
+	// TicketTypes, when present, replaces the event's ticket types.
+	TicketTypes *[]TicketTypeRequest `json:"ticket_types,omitempty" validate:"omitempty,max=20,unique=Name,dive"`
+}
+
+// Event import modes. An atomic import applies every row or, if any row
+// fails, none; a best-effort import applies the rows that succeed.
+const (
+	ImportModeAtomic     = "atomic"
+	ImportModeBestEffort = "best_effort"
+)
+
+// What an import did with a row.
+const (
+	ImportActionCreated   = "created"
+	ImportActionUpdated   = "updated"
+	ImportActionUnchanged = "unchanged"
+	ImportActionFailed    = "failed"
+	ImportActionSkipped   = "skipped" // valid, but an atomic import failed elsewhere
+)
+
+// EventImportRow is one event of an import file. Rows are matched to the
+// organizer's existing events by ExternalRef; TotalTickets is the event's
+// capacity, so re-importing an unchanged row changes nothing.
+type EventImportRow struct {
+	ExternalRef        string `json:"external_ref" validate:"required,max=100"`
+	CreateEventRequest `validate:"-"`
+
+	// ParseErrors are the fields the file had values for that could not
+	// be read, such as a number that isn't one.
+	ParseErrors []validation.FieldError `json:"-"`
+}
+
+// EventImportOptions controls how an import applies its rows. Mode
+// defaults to atomic.
+type EventImportOptions struct {
+	Mode   string
+	DryRun bool
+}
+
+// EventImportQuery is the query string of the import endpoint. Format
+// defaults to csv for a text/csv body and json otherwise.
+type EventImportQuery struct {
+	Format string `query:"format" json:"format" validate:"omitempty,oneof=csv json"`
+	Mode   string `query:"mode" json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	DryRun bool   `query:"dry_run" json:"dry_run"`
+}
+
+// EventImportResult reports an import row by row. Applied is false when
+// nothing was written: for a dry run, or an atomic import with a failed row.
+type EventImportResult struct {
+	Mode      string                  `json:"mode"`
+	DryRun    bool                    `json:"dry_run"`
+	Applied   bool                    `json:"applied"`
+	Created   int                     `json:"created"`
+	Updated   int                     `json:"updated"`
+	Unchanged int                     `json:"unchanged"`
+	Failed    int                     `json:"failed"`
+	Rows      []*EventImportRowResult `json:"rows"`
+}
+
+// EventImportRowResult is the outcome of one row. Row counts from 1 and
+// leaves out the CSV header line.
+type EventImportRowResult struct {
+	Row         int                     `json:"row"`
+	ExternalRef string                  `json:"external_ref"`
+	Action      string                  `json:"action"`
+	EventID     int                     `json:"event_id,omitempty"`
+	Errors      []validation.FieldError `json:"errors,omitempty"`
 }
 
 type EventStatistics struct {
@@ -94,18 +482,241 @@ type EventStatistics struct {
 	PendingBooking int     `json:"pending_bookings"`
 }
 
+// Analytics intervals.
+const (
+	AnalyticsHour = "hour"
+	AnalyticsDay  = "day"
+)
+
+// AnalyticsRange selects the window and buckets of an analytics query. From
+// is inclusive, To exclusive; buckets start on whole hours or days in
+// Timezone, an IANA name.
+type AnalyticsRange struct {
+	Interval string
+	From     time.Time
+	To       time.Time
+	Timezone string
+}
+
+// AnalyticsQuery is the query string of the analytics endpoints.
+type AnalyticsQuery struct {
+	Interval string `query:"interval" json:"interval" validate:"omitempty,oneof=hour day"`
+	From     string `query:"from" json:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To       string `query:"to" json:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Timezone string `query:"tz" json:"tz" validate:"omitempty,max=64"`
+}
+
+// BookingActivity is the part of a booking analytics aggregates.
+type BookingActivity struct {
+	EventID     int
+	Status      BookingStatus
+	TicketType  string
+	TicketCount int
+	TotalPrice  float64
+	CreatedAt   time.Time
+	ConfirmedAt *time.Time
+	CancelledAt *time.Time
+	ExpiredAt   *time.Time
+}
+
+// AnalyticsBucket counts what happened to an event's bookings in one hour or
+// day: each booking is counted when it was created, confirmed, cancelled or
+// expired.
+type AnalyticsBucket struct {
+	Start             time.Time `json:"start"`
+	BookingsCreated   int       `json:"bookings_created"`
+	BookingsConfirmed int       `json:"bookings_confirmed"`
+	BookingsCancelled int       `json:"bookings_cancelled"`
+	BookingsExpired   int       `json:"bookings_expired"`
+	TicketsSold       int       `json:"tickets_sold"`
+	Revenue           float64   `json:"revenue"`
+}
+
+// AnalyticsTotals sums the buckets of a range. The rates are over the
+// bookings created in the range: the share of them now confirmed, expired
+// or cancelled.
+type AnalyticsTotals struct {
+	BookingsCreated   int     `json:"bookings_created"`
+	BookingsConfirmed int     `json:"bookings_confirmed"`
+	BookingsCancelled int     `json:"bookings_cancelled"`
+	BookingsExpired   int     `json:"bookings_expired"`
+	TicketsSold       int     `json:"tickets_sold"`
+	Revenue           float64 `json:"revenue"`
+	ConversionRate    float64 `json:"conversion_rate"`
+	ExpiryRate        float64 `json:"expiry_rate"`
+	CancellationRate  float64 `json:"cancellation_rate"`
+}
+
+// TicketTypeSales is what one ticket type sold in a range, counted when the
+// bookings were confirmed. TicketType is empty for the event's default
+// ticket.
+type TicketTypeSales struct {
+	TicketType  string  `json:"ticket_type"`
+	TicketsSold int     `json:"tickets_sold"`
+	Revenue     float64 `json:"revenue"`
+}
+
+type EventAnalytics struct {
+	EventID      int               `json:"event_id"`
+	EventName    string            `json:"event_name"`
+	Interval     string            `json:"interval"`
+	Timezone     string            `json:"timezone"`
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	Totals       AnalyticsTotals   `json:"totals"`
+	ByTicketType []TicketTypeSales `json:"by_ticket_type"`
+	Series       []AnalyticsBucket `json:"series"`
+}
+
+// EventAnalyticsSummary is one row of the comparison across an organizer's
+// events.
+type EventAnalyticsSummary struct {
+	EventID     int             `json:"event_id"`
+	EventName   string          `json:"event_name"`
+	DateTime    time.Time       `json:"date_time"`
+	Capacity    int             `json:"capacity"`
+	TicketPrice float64         `json:"ticket_price"`
+	Totals      AnalyticsTotals `json:"totals"`
+	SellThrough float64         `json:"sell_through"` // tickets sold in the range / capacity
+}
+
 type CreateUserRequest struct {
-	Name  string `json:"name" validate:"required"`
-	Email string `json:"email" validate:"required,email"`
+	Name   string `json:"name" validate:"required,max=255"`
+	Email  string `json:"email" validate:"required,email,max=255"`
+	Locale string `json:"locale" validate:"omitempty,oneof=en vi"`
 }
 
 type LoginRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+// MaxTicketsPerOrder caps CreateBookingRequest.TicketCount, and the
+// per-event order limits; keep the max tags in step with it.
+const MaxTicketsPerOrder = 10
+
 type CreateBookingRequest struct {
-	EventID     int `json:"event_id" validate:"required"`
-	TicketCount int `json:"ticket_count" validate:"required,min=1"`
+	EventID           int    `json:"event_id" validate:"required,min=1"`
+	TicketCount       int    `json:"ticket_count" validate:"required,min=1,max=10"`
+	PaymentInstrument string `json:"payment_instrument" validate:"max=255"`
+	// TicketType names one of the event's ticket types; empty books the
+	// default ticket at the event's price.
+	TicketType string `json:"ticket_type,omitempty" validate:"max=50"`
+}
+
+type BookingEventPayload struct {
+	BookingID   int           `json:"booking_id"`
+	UserID      int           `json:"user_id"`
+	EventID     int           `json:"event_id"`
+	TicketCount int           `json:"ticket_count"`
+	TotalPrice  float64       `json:"total_price"`
+	Status      BookingStatus `json:"status"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	OccurredAt  time.Time     `json:"occurred_at"`
+}
+
+type EventUpdatedPayload struct {
+	EventID          int       `json:"event_id"`
+	Name             string    `json:"name"`
+	DateTime         time.Time `json:"date_time"`
+	PreviousDateTime time.Time `json:"previous_date_time"`
+	TotalTickets     int       `json:"total_tickets"`
+	TicketPrice      float64   `json:"ticket_price"`
+	OccurredAt       time.Time `json:"occurred_at"`
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	Secret     string   `json:"secret" validate:"omitempty,min=32,max=255"`
+	EventTypes []string `json:"event_types"`
+}
+
+type UpdateWebhookRequest struct {
+	URL        *string   `json:"url,omitempty" validate:"omitempty,url"`
+	EventTypes *[]string `json:"event_types,omitempty"`
+	Active     *bool     `json:"active,omitempty"`
+}
+
+type ExpireBookingPayload struct {
+	BookingID int `json:"booking_id"`
+}
+
+// SendEmailPayload is a rendered email waiting in the job queue.
+type SendEmailPayload struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+type BookingFilter struct {
+	EventID int
+	UserID  int
+	Status  BookingStatus
+	Limit   int
+}
+
+// Attendee is one row of an event's attendee list.
+type Attendee struct {
+	BookingID   int           `json:"booking_id"`
+	Reference   string        `json:"reference"`
+	UserID      int           `json:"user_id"`
+	Name        string        `json:"name"`
+	Email       string        `json:"email"`
+	TicketCount int           `json:"ticket_count"`
+	TicketType  string        `json:"ticket_type"`
+	Status      BookingStatus `json:"status"`
+	BookedAt    time.Time     `json:"booked_at"`
+	CheckedInAt *time.Time    `json:"checked_in_at"`
+}
+
+// CalendarEntry is one event of an iCalendar feed. UID stays the same for
+// the life of the event and Sequence grows with each change, so calendar
+// apps update their copy instead of adding another.
+type CalendarEntry struct {
+	UID         string
+	Sequence    int
+	Summary     string
+	Description string
+	Start       time.Time
+	Cancelled   bool
+	UpdatedAt   time.Time
+}
+
+// CalendarToken is a newly issued private calendar feed. The token is not
+// stored, so it can't be shown again; issuing another replaces it.
+type CalendarToken struct {
+	Token    string `json:"token"`
+	FeedPath string `json:"feed_path"`
+}
+
+// AttendeeExportQuery is the query string of the attendee export.
+type AttendeeExportQuery struct {
+	Format  string `query:"format" json:"format" validate:"omitempty,oneof=csv xlsx"`
+	Columns string `query:"columns" json:"columns" validate:"omitempty,max=500"`
+}
+
+// InventoryRecount is the outcome of recomputing an event's available
+// tickets from its capacity and the bookings still holding tickets.
+type InventoryRecount struct {
+	EventID     int  `json:"event_id"`
+	Capacity    int  `json:"capacity"`
+	HeldTickets int  `json:"held_tickets"`
+	Before      int  `json:"before"`
+	After       int  `json:"after"`
+	Oversold    int  `json:"oversold"`
+	Changed     bool `json:"changed"`
+}
+
+// AuditLogQuery is the query string of the admin audit log endpoint. From
+// and To are RFC 3339 timestamps bounding created_at, From inclusive.
+type AuditLogQuery struct {
+	EntityType string `query:"entity_type" json:"entity_type" validate:"omitempty,max=50"`
+	EntityID   int    `query:"entity_id" json:"entity_id" validate:"min=0"`
+	ActorID    int    `query:"actor_id" json:"actor_id" validate:"min=0"`
+	Action     string `query:"action" json:"action" validate:"omitempty,max=100"`
+	From       string `query:"from" json:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" json:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit      int    `query:"limit" json:"limit" validate:"min=0,max=1000"`
 }
 
 type BookingWithDetails struct {
@@ -0,0 +1,231 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer delivers a rendered message. Implementations must be safe for
+// concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPMailer sends multipart/alternative mail through an SMTP server.
+type SMTPMailer struct {
+	host    string
+	addr    string
+	auth    smtp.Auth
+	from    string
+	timeout time.Duration
+}
+
+// NewSMTPMailer sends through host:port. Each message must be handed over
+// within timeout, or by the deadline of the context passed to Send if that is
+// sooner, so a stuck server can't hold a job past its visibility timeout.
+func NewSMTPMailer(host string, port int, username, password, from string, timeout time.Duration) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		host:    host,
+		addr:    fmt.Sprintf("%s:%d", host, port),
+		auth:    auth,
+		from:    from,
+		timeout: timeout,
+	}
+}
+
+// Send does what smtp.SendMail does, on a connection that gives up at the
+// deadline or when ctx is cancelled.
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	body, err := encodeMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("can't connect to SMTP server: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	// unblock reads and writes as soon as ctx is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return m.sendError(ctx, err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return m.sendError(ctx, err)
+		}
+	}
+	if m.auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(m.auth); err != nil {
+				return m.sendError(ctx, err)
+			}
+		}
+	}
+	if err := client.Mail(m.from); err != nil {
+		return m.sendError(ctx, err)
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return m.sendError(ctx, err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return m.sendError(ctx, err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return m.sendError(ctx, err)
+	}
+	if err := w.Close(); err != nil {
+		return m.sendError(ctx, err)
+	}
+	return m.sendError(ctx, client.Quit())
+}
+
+// sendError reports a timeout or cancellation as such rather than as the
+// I/O error it caused.
+func (m *SMTPMailer) sendError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("smtp: %w: %v", ctx.Err(), err)
+	}
+	return fmt.Errorf("smtp: %w", err)
+}
+
+// FileMailer writes every message as an .eml file, handy for local
+// development without an SMTP server.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("can't create mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	body, err := encodeMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"), randomToken(4))
+	return os.WriteFile(filepath.Join(m.dir, name), body, 0o644)
+}
+
+// MemoryMailer keeps sent messages in memory, for tests.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *MemoryMailer) Messages() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Message(nil), m.messages...)
+}
+
+func encodeMessage(from string, msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@event-booking>\r\n", randomToken(16))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+	for _, part := range parts {
+		if part.content == "" {
+			continue
+		}
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func randomToken(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
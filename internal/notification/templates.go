@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+const (
+	TypeBookingPending   = "booking_pending"
+	TypeBookingConfirmed = "booking_confirmed"
+	TypeBookingExpired   = "booking_expired"
+	TypeBookingCancelled = "booking_cancelled"
+	TypeEventChanged     = "event_changed"
+	TypeEventReminder    = "event_reminder"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// dateLayouts holds how each supported locale formats date and time.
+var dateLayouts = map[string]string{
+	"en": "Mon, 02 Jan 2006 15:04 MST",
+	"vi": "15:04 02/01/2006 (MST)",
+}
+
+// TemplateData is the data every message template is rendered with. Fields
+// that don't apply to a message type are left zero.
+type TemplateData struct {
+	UserName         string
+	EventName        string
+	EventDateTime    time.Time
+	PreviousDateTime time.Time
+	Reference        string
+	TicketCount      int
+	TotalPrice       float64
+	ExpiresAt        time.Time
+	Tickets          []string
+}
+
+type localeTemplates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer turns a message type and TemplateData into a localized subject,
+// plain-text and HTML body.
+type Renderer struct {
+	defaultLocale string
+	locales       map[string]*localeTemplates
+}
+
+func NewRenderer(defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		defaultLocale: defaultLocale,
+		locales:       make(map[string]*localeTemplates),
+	}
+
+	for locale, layout := range dateLayouts {
+		funcs := map[string]interface{}{
+			"datetime": func(t time.Time) string { return t.Format(layout) },
+			"money":    func(amount float64) string { return fmt.Sprintf("$%.2f", amount) },
+		}
+
+		text, err := texttemplate.New(locale).Funcs(funcs).ParseFS(templateFS, "templates/"+locale+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s text templates: %w", locale, err)
+		}
+		html, err := htmltemplate.New(locale).Funcs(funcs).ParseFS(templateFS, "templates/"+locale+".html")
+		if err != nil {
+			return nil, fmt.Errorf("can't parse %s html templates: %w", locale, err)
+		}
+
+		r.locales[locale] = &localeTemplates{text: text, html: html}
+	}
+
+	if _, ok := r.locales[defaultLocale]; !ok {
+		return nil, fmt.Errorf("unsupported default locale %q", defaultLocale)
+	}
+	return r, nil
+}
+
+// Render falls back to the default locale when locale isn't supported.
+func (r *Renderer) Render(locale, messageType string, data *TemplateData) (*Message, error) {
+	templates, ok := r.locales[strings.ToLower(locale)]
+	if !ok {
+		templates = r.locales[r.defaultLocale]
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := templates.text.ExecuteTemplate(&subject, messageType+".subject", data); err != nil {
+		return nil, fmt.Errorf("can't render %s subject: %w", messageType, err)
+	}
+	if err := templates.text.ExecuteTemplate(&text, messageType+".text", data); err != nil {
+		return nil, fmt.Errorf("can't render %s text: %w", messageType, err)
+	}
+	if err := templates.html.ExecuteTemplate(&html, messageType+".html", data); err != nil {
+		return nil, fmt.Errorf("can't render %s html: %w", messageType, err)
+	}
+
+	return &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,342 @@
+// Package openapi describes the HTTP API as an OpenAPI 3.1 document. Schemas
+// are reflected from the models DTOs and the handler.Response envelope, so
+// they cannot drift from what the handlers encode. Operations are listed by
+// hand in operations.go; a test checks that list against the routes the
+// router registers.
+package openapi
+
+import (
+	"encoding/json"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/utils"
+	"event-booking-be/internal/validation"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	SpecPath = "/openapi.json"
+	DocsPath = "/docs"
+)
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]*Response      `json:"responses"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+const securityScheme = "userId"
+
+// errorResponses are the shared error replies, by status.
+var errorResponses = map[int]string{
+	http.StatusBadRequest:          "BadRequest",
+	http.StatusUnauthorized:        "Unauthorized",
+	http.StatusForbidden:           "Forbidden",
+	http.StatusNotFound:            "NotFound",
+	http.StatusConflict:            "Conflict",
+	http.StatusUnprocessableEntity: "ValidationFailed",
+	http.StatusTooManyRequests:     "TooManyRequests",
+	http.StatusInternalServerError: "InternalError",
+	http.StatusServiceUnavailable:  "ServiceUnavailable",
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Build assembles the document.
+func Build() *Document {
+	g := newGenerator()
+
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info: Info{
+			Title:   "Event Booking API",
+			Version: "1.0.0",
+			Description: "Every JSON reply uses the Response envelope: `success`, then `data` on success " +
+				"or `code` and `error` on failure. Clients should branch on `code`, not on the message.",
+		},
+		Paths: map[string]PathItem{},
+		Components: Components{
+			Schemas:   g.schemas,
+			Responses: map[string]*Response{},
+			SecuritySchemes: map[string]SecurityScheme{
+				securityScheme: {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "X-User-ID",
+					Description: "ID of the calling user.",
+				},
+			},
+		},
+	}
+
+	envelope := g.response(handler.Response{})
+	g.schemas["ErrorCode"] = &Schema{
+		Type:        "string",
+		Description: "Stable machine-readable error code.",
+		Enum:        utils.ErrorCodes,
+	}
+	g.schemas["ErrorResponse"] = &Schema{
+		AllOf: []*Schema{envelope, {
+			Type:     "object",
+			Required: []string{"success", "code", "error"},
+			Properties: map[string]*Schema{
+				"success": {Type: "boolean", Const: false},
+				"code": {
+					Description: "One of ErrorCode, or for errors raised before a handler runs " +
+						"(unknown route, wrong method) the upper-cased HTTP status text, e.g. NOT_FOUND.",
+					AnyOf: []*Schema{ref("ErrorCode"), {Type: "string", Pattern: "^[A-Z_]+$"}},
+				},
+				"details": {
+					Type:        "array",
+					Description: "Present with VALIDATION_FAILED: one entry per failed rule.",
+					Items:       g.response(validation.FieldError{}),
+				},
+			},
+		}},
+	}
+	for status, name := range errorResponses {
+		doc.Components.Responses[name] = &Response{
+			Description: http.StatusText(status),
+			Content:     jsonContent(ref("ErrorResponse")),
+		}
+	}
+	doc.Components.Responses["TooManyRequests"].Headers = map[string]Header{
+		"Retry-After":         {Description: "Seconds until a request will be accepted.", Schema: &Schema{Type: "integer"}},
+		"RateLimit-Limit":     {Description: "Requests allowed per window.", Schema: &Schema{Type: "integer"}},
+		"RateLimit-Remaining": {Description: "Requests left in the current window.", Schema: &Schema{Type: "integer"}},
+		"RateLimit-Reset":     {Description: "Seconds until the window frees up a request.", Schema: &Schema{Type: "integer"}},
+	}
+
+	// webhook bodies answer no route, but partners consume them
+	g.response(models.BookingEventPayload{})
+	g.response(models.EventUpdatedPayload{})
+
+	tags := map[string]bool{}
+	for _, op := range operations(g) {
+		doc.add(op, envelope)
+		tags[op.tag] = true
+	}
+	for _, tag := range tagOrder {
+		if tags[tag.Name] {
+			doc.Tags = append(doc.Tags, tag)
+		}
+	}
+
+	return doc
+}
+
+func (d *Document) add(op operation, envelope *Schema) {
+	o := &Operation{
+		Tags:        []string{op.tag},
+		Summary:     op.summary,
+		Description: op.description,
+		OperationID: op.id,
+		Responses:   map[string]*Response{},
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(op.path, -1) {
+		// IDs are integers; other parameters, such as tokens, are strings
+		schema := &Schema{Type: "integer"}
+		if match[1] != "id" {
+			schema = &Schema{Type: "string"}
+		}
+		o.Parameters = append(o.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   schema,
+		})
+	}
+	o.Parameters = append(o.Parameters, op.query...)
+
+	if op.body != nil {
+		o.RequestBody = &RequestBody{Required: true, Content: jsonContent(op.body)}
+	}
+
+	status := op.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case op.content != "":
+		success.Content = map[string]MediaType{op.content: {Schema: op.data}}
+	case op.raw:
+		success.Content = jsonContent(op.data)
+	default:
+		success.Content = jsonContent(&Schema{AllOf: []*Schema{envelope, {
+			Type:       "object",
+			Required:   []string{"success", "data"},
+			Properties: map[string]*Schema{"data": op.data},
+		}}})
+	}
+	o.Responses[statusKey(status)] = success
+
+	errs := append([]int{}, op.errors...)
+	if op.body != nil {
+		errs = append(errs, http.StatusBadRequest, http.StatusUnprocessableEntity)
+	}
+	if op.auth {
+		o.Security = []map[string][]string{{securityScheme: {}}}
+		errs = append(errs, http.StatusUnauthorized)
+	}
+	// every API route group is rate limited
+	if strings.HasPrefix(op.path, apiPrefix+"/") {
+		errs = append(errs, http.StatusTooManyRequests)
+	}
+	if !op.raw && op.content == "" {
+		errs = append(errs, http.StatusInternalServerError)
+	}
+	for _, status := range errs {
+		o.Responses[statusKey(status)] = &Response{Ref: "#/components/responses/" + errorResponses[status]}
+	}
+
+	item, ok := d.Paths[op.path]
+	if !ok {
+		item = PathItem{}
+		d.Paths[op.path] = item
+	}
+	item[strings.ToLower(op.method)] = o
+}
+
+// Has reports whether the document describes method on path, with path in
+// Fiber's form (/events/:id).
+func (d *Document) Has(method, path string) bool {
+	item, ok := d.Paths[FromFiberPath(path)]
+	if !ok {
+		return false
+	}
+	_, ok = item[strings.ToLower(method)]
+	return ok
+}
+
+var fiberParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// FromFiberPath converts a Fiber route template to an OpenAPI path.
+func FromFiberPath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return fiberParam.ReplaceAllString(path, "{$1}")
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{fiber.MIMEApplicationJSON: {Schema: s}}
+}
+
+func statusKey(status int) string {
+	return strconv.Itoa(status)
+}
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+)
+
+// Handler serves the document as JSON. It is built on first request and
+// cached, since it only changes with the binary.
+func Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		specOnce.Do(func() {
+			specJSON, _ = json.Marshal(Build())
+		})
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(specJSON)
+	}
+}
+
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+<title>Event Booking API</title>
+<meta charset="utf-8"/>
+<meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+<redoc spec-url="` + SpecPath + `"></redoc>
+<script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>`
+
+// DocsHandler serves a Redoc page rendering the document.
+func DocsHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(docsPage)
+	}
+}
@@ -0,0 +1,372 @@
+package openapi
+
+import (
+	"event-booking-be/internal/importer"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/realtime"
+	"fmt"
+	"net/http"
+)
+
+// operation describes one route. data is the payload of the success reply:
+// wrapped in the Response envelope unless raw is set, and served as content
+// instead of JSON when content is set.
+type operation struct {
+	method      string
+	path        string
+	id          string
+	tag         string
+	summary     string
+	description string
+	auth        bool
+	query       []Parameter
+	body        *Schema
+	status      int
+	data        *Schema
+	raw         bool
+	content     string
+	errors      []int
+}
+
+var tagOrder = []Tag{
+	{Name: "auth", Description: "Registration and login."},
+	{Name: "events", Description: "Events and their ticket inventory."},
+	{Name: "bookings", Description: "Reserving, paying for and cancelling tickets."},
+	{Name: "webhooks", Description: "Organizer webhook subscriptions."},
+	{Name: "users", Description: "The calling user."},
+	{Name: "organizer", Description: "Sales analytics and attendee lists for the events the caller organizes."},
+	{Name: "admin", Description: "Operator endpoints, for admin users only."},
+	{Name: "system", Description: "Health, metrics and this document."},
+}
+
+const apiPrefix = "/api/v1"
+
+func message() *Schema {
+	return &Schema{
+		Type:       "object",
+		Required:   []string{"message"},
+		Properties: map[string]*Schema{"message": {Type: "string"}},
+	}
+}
+
+// operations lists every route the server registers; keep it in step with
+// routes.Router.Setup and cmd/api.
+func operations(g *generator) []operation {
+	return []operation{
+		// auth
+		{
+			method: http.MethodPost, path: apiPrefix + "/auth/register", id: "register", tag: "auth",
+			summary: "Register a user",
+			body:    g.request(models.CreateUserRequest{}),
+			status:  http.StatusCreated,
+			data:    g.response(models.User{}),
+			errors:  []int{http.StatusConflict},
+		},
+		{
+			method: http.MethodPost, path: apiPrefix + "/auth/login", id: "login", tag: "auth",
+			summary: "Log in by email",
+			body:    g.request(models.LoginRequest{}),
+			data: &Schema{
+				Type:     "object",
+				Required: []string{"user", "message"},
+				Properties: map[string]*Schema{
+					"user":    g.response(models.User{}),
+					"message": {Type: "string"},
+				},
+			},
+			errors: []int{http.StatusUnauthorized},
+		},
+
+		// events
+		{
+			method: http.MethodGet, path: apiPrefix + "/events", id: "listEvents", tag: "events",
+			summary: "List events",
+			data:    g.list(models.Event{}),
+		},
+		{
+			method: http.MethodPost, path: apiPrefix + "/events", id: "createEvent", tag: "events",
+			summary:     "Create an event",
+			description: "The caller becomes the event's organizer.",
+			auth:        true,
+			body:        g.request(models.CreateEventRequest{}),
+			status:      http.StatusCreated,
+			data:        g.response(models.Event{}),
+		},
+		{
+			method: http.MethodPost, path: apiPrefix + "/events/import", id: "importEvents", tag: "events",
+			summary: "Import events from a file",
+			description: fmt.Sprintf("The body is a JSON array of events, or a CSV file (Content-Type text/csv, or format=csv) whose "+
+				"header names the same fields. Rows are matched to the caller's events by external_ref: new references "+
+				"create events, known ones update them, with total_tickets as the capacity. An atomic import (the default) "+
+				"applies nothing if any row fails; best_effort applies the rows that succeed; dry_run rolls everything "+
+				"back. Row errors are reported per row in the result, not as an error status. At most %d rows.", importer.MaxRows),
+			auth:   true,
+			query:  g.query(models.EventImportQuery{}),
+			body:   &Schema{Type: "array", Items: g.request(models.EventImportRow{})},
+			data:   g.response(models.EventImportResult{}),
+			errors: []int{http.StatusBadRequest},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/events/{id}", id: "getEvent", tag: "events",
+			summary: "Get an event",
+			data:    g.response(models.Event{}),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPut, path: apiPrefix + "/events/{id}", id: "updateEvent", tag: "events",
+			summary:     "Update an event",
+			description: "Only the fields present are changed. Only the event's organizer or an admin may update it; anyone else gets 404.",
+			auth:        true,
+			body:        g.request(models.UpdateEventRequest{}),
+			data:        g.response(models.Event{}),
+			errors:      []int{http.StatusNotFound},
+		},
+		{
+			method: http.MethodDelete, path: apiPrefix + "/events/{id}", id: "deleteEvent", tag: "events",
+			summary:     "Delete an event",
+			description: "Only the event's organizer or an admin may delete it; anyone else gets 404.",
+			auth:        true,
+			data:        message(),
+			errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/events/calendar.ics", id: "getUpcomingCalendar", tag: "events",
+			summary: "Calendar feed of upcoming events",
+			description: "An iCalendar feed of every event yet to start. Events deleted before they start stay in the " +
+				"feed with STATUS:CANCELLED, so subscribed calendars drop them.",
+			content: "text/calendar",
+			data:    &Schema{Type: "string"},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/events/{id}/calendar.ics", id: "getEventCalendar", tag: "events",
+			summary: "Download an event as iCalendar",
+			description: "The UID is the same in every feed and SEQUENCE grows when the name, description or date " +
+				"changes, so importing it again updates the calendar entry.",
+			content: "text/calendar",
+			data:    &Schema{Type: "string"},
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/events/{id}/statistics", id: "getEventStatistics", tag: "events",
+			summary: "Get sales statistics for an event",
+			data:    g.response(models.EventStatistics{}),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/events/{id}/availability/stream", id: "streamAvailability", tag: "events",
+			summary: "Stream ticket availability",
+			description: "Server-Sent Events. Each `availability` event carries an Availability object as its data: " +
+				"the current value first, then every change. Comment lines are sent as heartbeats.",
+			content: "text/event-stream",
+			data:    g.response(realtime.Availability{}),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError, http.StatusServiceUnavailable},
+		},
+
+		// bookings
+		{
+			method: http.MethodPost, path: apiPrefix + "/bookings", id: "createBooking", tag: "bookings",
+			summary:     "Reserve tickets",
+			description: "The booking is held as PENDING until confirmed or until it expires.",
+			auth:        true,
+			body:        g.request(models.CreateBookingRequest{}),
+			status:      http.StatusCreated,
+			data:        g.response(models.Booking{}),
+			errors:      []int{http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/bookings", id: "listBookings", tag: "bookings",
+			summary: "List the caller's bookings",
+			auth:    true,
+			data:    g.list(models.Booking{}),
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/bookings/{id}", id: "getBooking", tag: "bookings",
+			summary: "Get a booking",
+			description: "Only the booking's owner, the organizer of its event and admins can see it; " +
+				"anyone else gets 404, as do the history, confirm and cancel routes.",
+			auth:   true,
+			data:   g.response(models.BookingWithDetails{}),
+			errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/bookings/{id}/history", id: "getBookingHistory", tag: "bookings",
+			summary:     "Get a booking's status history",
+			description: "Every status change, oldest first; the first entry is the booking's creation.",
+			auth:        true,
+			data:        g.list(models.BookingStatusChange{}),
+			errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: apiPrefix + "/bookings/{id}/confirm", id: "confirmBooking", tag: "bookings",
+			summary: "Confirm payment for a booking",
+			auth:    true,
+			data:    message(),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			method: http.MethodPost, path: apiPrefix + "/bookings/{id}/cancel", id: "cancelBooking", tag: "bookings",
+			summary: "Cancel a booking",
+			auth:    true,
+			data:    message(),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		},
+		{
+			method: http.MethodPost, path: apiPrefix + "/bookings/{id}/check-in", id: "checkInBooking", tag: "bookings",
+			summary:     "Check a booking in at the door",
+			description: "Only the organizer of the booking's event and admins can check bookings in. A booking must be confirmed and can be checked in once.",
+			auth:        true,
+			data:        g.response(models.Booking{}),
+			errors:      []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+		},
+
+		// webhooks
+		{
+			method: http.MethodPost, path: apiPrefix + "/webhooks", id: "createWebhook", tag: "webhooks",
+			summary:     "Subscribe to domain events",
+			description: "An empty event_types subscribes to every event. The url must resolve to a public address. A secret of at least 32 characters may be given, otherwise one is generated; this reply is the only one that shows it.",
+			auth:        true,
+			body:        g.request(models.CreateWebhookRequest{}),
+			status:      http.StatusCreated,
+			data:        g.response(models.CreatedWebhookSubscription{}),
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/webhooks", id: "listWebhooks", tag: "webhooks",
+			summary: "List the caller's webhook subscriptions",
+			auth:    true,
+			data:    g.list(models.WebhookSubscription{}),
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/webhooks/{id}", id: "getWebhook", tag: "webhooks",
+			summary: "Get a webhook subscription",
+			auth:    true,
+			data:    g.response(models.WebhookSubscription{}),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPut, path: apiPrefix + "/webhooks/{id}", id: "updateWebhook", tag: "webhooks",
+			summary:     "Update a webhook subscription",
+			description: "Re-activating a subscription resets its failure count.",
+			auth:        true,
+			body:        g.request(models.UpdateWebhookRequest{}),
+			data:        g.response(models.WebhookSubscription{}),
+			errors:      []int{http.StatusNotFound},
+		},
+		{
+			method: http.MethodDelete, path: apiPrefix + "/webhooks/{id}", id: "deleteWebhook", tag: "webhooks",
+			summary: "Delete a webhook subscription",
+			auth:    true,
+			data:    message(),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/webhooks/{id}/deliveries", id: "listWebhookDeliveries", tag: "webhooks",
+			summary: "List recent deliveries for a subscription",
+			auth:    true,
+			data:    g.list(models.WebhookDelivery{}),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: apiPrefix + "/webhooks/{id}/test", id: "testWebhook", tag: "webhooks",
+			summary: "Send a test event to a subscription",
+			auth:    true,
+			data:    g.response(models.WebhookDelivery{}),
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+
+		// users
+		{
+			method: http.MethodGet, path: apiPrefix + "/users/profile", id: "getProfile", tag: "users",
+			summary: "Get the caller's profile",
+			auth:    true,
+			data:    g.response(models.User{}),
+			errors:  []int{http.StatusNotFound},
+		},
+		{
+			method: http.MethodPost, path: apiPrefix + "/users/calendar-token", id: "issueCalendarToken", tag: "users",
+			summary: "Issue a private calendar feed URL",
+			description: "Returns a new token and the path of the caller's private feed. The token is shown only " +
+				"once; issuing another revokes the previous one.",
+			auth:   true,
+			status: http.StatusCreated,
+			data:   g.response(models.CalendarToken{}),
+			errors: []int{http.StatusNotFound},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/calendar/{token}/bookings.ics", id: "getUserCalendar", tag: "users",
+			summary: "Private calendar feed of the caller's bookings",
+			description: "An iCalendar feed of the events the token's owner has confirmed bookings for, one entry per " +
+				"event listing the bookings. The token in the URL is the only credential, so calendar apps can " +
+				"subscribe without an auth header. Deleted events appear with STATUS:CANCELLED.",
+			content: "text/calendar",
+			data:    &Schema{Type: "string"},
+			errors:  []int{http.StatusNotFound},
+		},
+
+		// organizer
+		{
+			method: http.MethodGet, path: apiPrefix + "/organizer/events/analytics", id: "compareEventAnalytics", tag: "organizer",
+			summary:     "Compare the caller's events",
+			description: "Totals over the range for every event the caller organizes, in date order.",
+			auth:        true,
+			query:       g.query(models.AnalyticsQuery{}),
+			data:        g.list(models.EventAnalyticsSummary{}),
+			errors:      []int{http.StatusBadRequest, http.StatusUnprocessableEntity},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/organizer/events/{id}/analytics", id: "getEventAnalytics", tag: "organizer",
+			summary: "Get an event's sales over time",
+			description: "Hourly or daily buckets in the tz timezone (default daily, UTC, over the last 30 days). " +
+				"Hourly ranges are limited to 31 days and daily ones to 366. Only the event's organizer and admins " +
+				"can see it; anyone else gets 404.",
+			auth:   true,
+			query:  g.query(models.AnalyticsQuery{}),
+			data:   g.response(models.EventAnalytics{}),
+			errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity},
+		},
+		{
+			method: http.MethodGet, path: apiPrefix + "/organizer/events/{id}/attendees", id: "exportAttendees", tag: "organizer",
+			summary: "Export an event's attendees",
+			description: "The confirmed attendees in booking order, streamed as a CSV file, or as an XLSX workbook " +
+				"with format=xlsx. columns is a comma-separated subset of booking_id, reference, name, email, " +
+				"ticket_count, status and booked_at, in the order wanted; the default is all of them. Only the " +
+				"event's organizer and admins can export it; anyone else gets 404.",
+			auth:    true,
+			query:   g.query(models.AttendeeExportQuery{}),
+			content: "text/csv",
+			data:    &Schema{Type: "string"},
+			errors:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+		},
+
+		// admin
+		{
+			method: http.MethodGet, path: apiPrefix + "/admin/audit-logs", id: "listAuditLogs", tag: "admin",
+			summary: "List audit log entries",
+			description: "Newest first. Each entry names the actor, the action, the entity and the request, " +
+				"and carries the changed fields as a JSON object of field to {before, after}.",
+			auth:   true,
+			query:  g.query(models.AuditLogQuery{}),
+			data:   g.list(models.AuditLog{}),
+			errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusUnprocessableEntity},
+		},
+
+		// system
+		{
+			method: http.MethodGet, path: "/health", id: "health", tag: "system",
+			summary: "Report database, Redis and leader status",
+			raw:     true,
+			data:    &Schema{Type: "object"},
+		},
+		{
+			method: http.MethodGet, path: SpecPath, id: "openapi", tag: "system",
+			summary: "This document",
+			raw:     true,
+			data:    &Schema{Type: "object"},
+		},
+		{
+			method: http.MethodGet, path: DocsPath, id: "docs", tag: "system",
+			summary: "Rendered API reference",
+			content: "text/html",
+			data:    &Schema{Type: "string"},
+		},
+	}
+}
@@ -0,0 +1,246 @@
+package openapi
+
+import (
+	"event-booking-be/internal/models"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema 2020-12 the document uses.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// enums lists the values of the string types the API exposes as enums.
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(models.BookingStatus("")): {
+		string(models.BookingStatusPending),
+		string(models.BookingStatusConfirmed),
+		string(models.BookingStatusCancelled),
+		string(models.BookingStatusExpired),
+	},
+	reflect.TypeOf(models.WebhookDeliveryStatus("")): {
+		string(models.WebhookDeliveryPending),
+		string(models.WebhookDeliverySucceeded),
+		string(models.WebhookDeliveryFailed),
+	},
+}
+
+// generator reflects Go types into component schemas, keyed by type name.
+type generator struct {
+	schemas map[string]*Schema
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: map[string]*Schema{}}
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// response returns a reference to v's schema as the server writes it: every
+// field without omitempty is always present.
+func (g *generator) response(v interface{}) *Schema {
+	return g.schemaFor(reflect.TypeOf(v), false)
+}
+
+// request returns a reference to v's schema as clients send it: required
+// fields and bounds come from the validate tags.
+func (g *generator) request(v interface{}) *Schema {
+	return g.schemaFor(reflect.TypeOf(v), true)
+}
+
+// list wraps a response schema in an array.
+func (g *generator) list(v interface{}) *Schema {
+	return &Schema{Type: "array", Items: g.response(v)}
+}
+
+// query returns the query parameters of v, a struct bound with query tags.
+// Bounds come from the validate tags, as for request bodies.
+func (g *generator) query(v interface{}) []Parameter {
+	t := reflect.TypeOf(v)
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("query")
+		if name == "" || name == "-" {
+			continue
+		}
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		schema := g.schemaFor(field.Type, true)
+		applyRules(schema, rules)
+		params = append(params, Parameter{Name: name, In: "query", Required: hasRule(rules, "required"), Schema: schema})
+	}
+	return params
+}
+
+func (g *generator) schemaFor(t reflect.Type, request bool) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if values, ok := enums[t]; ok {
+		if _, done := g.schemas[t.Name()]; !done {
+			g.schemas[t.Name()] = &Schema{Type: "string", Enum: values}
+		}
+		return ref(t.Name())
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem(), request)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem(), request)}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if _, done := g.schemas[t.Name()]; !done {
+			// reserve the name first so self-referencing types terminate
+			g.schemas[t.Name()] = &Schema{}
+			*g.schemas[t.Name()] = *g.object(t, request)
+		}
+		return ref(t.Name())
+	}
+
+	return &Schema{}
+}
+
+func (g *generator) object(t reflect.Type, request bool) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(s, t, request)
+	return s
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type, request bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		// embedded structs are flattened, as encoding/json does
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(s, field.Type, request)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schemaFor(field.Type, request)
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		if prop.Ref == "" {
+			applyRules(prop, rules)
+		}
+		s.Properties[name] = prop
+
+		omitempty := strings.Contains(opts, "omitempty")
+		if (request && hasRule(rules, "required")) || (!request && !omitempty) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func hasRule(rules []string, name string) bool {
+	for _, rule := range rules {
+		if rule == name {
+			return true
+		}
+		// the rules after dive are for the elements
+		if rule == "dive" {
+			break
+		}
+	}
+	return false
+}
+
+// applyRules maps the validate tags the validation package understands onto
+// their JSON Schema keywords.
+func applyRules(s *Schema, rules []string) {
+	for _, rule := range rules {
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "min", "max":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			switch s.Type {
+			case "string":
+				length := int(n)
+				if key == "min" {
+					s.MinLength = &length
+				} else {
+					s.MaxLength = &length
+				}
+			case "integer", "number":
+				if key == "min" {
+					s.Minimum = &n
+				} else {
+					s.Maximum = &n
+				}
+			case "array":
+				count := int(n)
+				if key == "min" {
+					s.MinItems = &count
+				} else {
+					s.MaxItems = &count
+				}
+			}
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "datetime":
+			if param == time.RFC3339 {
+				s.Format = "date-time"
+			}
+		case "future":
+			s.Description = "Must be in the future."
+		case "dive":
+			return
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"context"
+	"event-booking-be/internal/models"
+	"log/slog"
+)
+
+// DispatchSink hands messages to an in-process consumer. The consumer runs
+// inside a transaction of its own per message, so rows it writes through the
+// repositories commit together with the message being marked as published,
+// and roll back if any sink fails it. Consumers shouldn't do network I/O.
+type DispatchSink struct {
+	name     string
+	dispatch func(ctx context.Context, message *models.OutboxMessage) error
+}
+
+func NewDispatchSink(name string, dispatch func(ctx context.Context, message *models.OutboxMessage) error) *DispatchSink {
+	return &DispatchSink{
+		name:     name,
+		dispatch: dispatch,
+	}
+}
+
+func (s *DispatchSink) Name() string {
+	return s.name
+}
+
+func (s *DispatchSink) Publish(ctx context.Context, message *models.OutboxMessage) error {
+	return s.dispatch(ctx, message)
+}
+
+// BestEffortSink hands messages to an in-process consumer outside any
+// transaction and only logs its errors, so it never holds a message back or
+// has the other sinks run again. It suits live updates that would be stale by
+// the time a retry ran.
+type BestEffortSink struct {
+	name   string
+	notify func(ctx context.Context, message *models.OutboxMessage) error
+	logger *slog.Logger
+}
+
+func NewBestEffortSink(name string, notify func(ctx context.Context, message *models.OutboxMessage) error, logger *slog.Logger) *BestEffortSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &BestEffortSink{
+		name:   name,
+		notify: notify,
+		logger: logger,
+	}
+}
+
+func (s *BestEffortSink) Name() string {
+	return s.name
+}
+
+func (s *BestEffortSink) Publish(ctx context.Context, message *models.OutboxMessage) error {
+	if err := s.notify(ctx, message); err != nil {
+		s.logger.WarnContext(ctx, "Best-effort outbox sink failed, dropping message",
+			"sink", s.name, "message_id", message.ID, "event_type", message.EventType, "error", err)
+	}
+	return nil
+}
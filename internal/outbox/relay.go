@@ -0,0 +1,196 @@
+package outbox
+
+import (
+	"context"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/tracing"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// Sink receives outbox messages. Delivery is at-least-once, so sinks and their
+// consumers should treat the message ID as an idempotency key.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, message *models.OutboxMessage) error
+}
+
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// ClaimTimeout is how long a fetched batch is held before another relay
+	// may pick up the messages it didn't get to.
+	ClaimTimeout time.Duration
+	Logger       *slog.Logger
+}
+
+// Relay polls the outbox and publishes pending messages to every sink. A
+// message is marked published only after all sinks accept it; on failure it
+// is retried with exponential backoff and later messages of the same aggregate
+// wait behind it.
+//
+// External sinks (log, Redis, HTTP) run outside any transaction. Dispatch
+// sinks then run in one transaction per message together with marking it
+// published, so if any sink fails the rows they wrote roll back and the retry
+// doesn't duplicate them. External sinks may see a message again on retry.
+type Relay struct {
+	outboxRepo    repository.OutboxRepository
+	db            *gorm.DB
+	sinks         []Sink
+	dispatchSinks []Sink
+	cfg           RelayConfig
+}
+
+func NewRelay(outboxRepo repository.OutboxRepository, db *gorm.DB, sinks []Sink, cfg RelayConfig) *Relay {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = 5 * time.Minute
+	}
+
+	r := &Relay{
+		outboxRepo: outboxRepo,
+		db:         db,
+		cfg:        cfg,
+	}
+	for _, sink := range sinks {
+		if _, ok := sink.(*DispatchSink); ok {
+			r.dispatchSinks = append(r.dispatchSinks, sink)
+		} else {
+			r.sinks = append(r.sinks, sink)
+		}
+	}
+	return r
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	r.cfg.Logger.InfoContext(ctx, "Outbox relay started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Drain(ctx); err != nil {
+				r.cfg.Logger.ErrorContext(ctx, "Error relaying outbox messages", "error", err)
+			}
+		}
+	}
+}
+
+// Drain processes batches until one publishes nothing. Each batch advances an
+// aggregate by at most one message, so follow-ups go out in the next batch.
+func (r *Relay) Drain(ctx context.Context) error {
+	for ctx.Err() == nil {
+		published, err := r.ProcessBatch(ctx)
+		if err != nil || published == 0 {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessBatch publishes one batch of pending messages and returns how many
+// were published.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	var messages []*models.OutboxMessage
+	err := repository.Transaction(ctx, r.db, func(ctx context.Context) error {
+		var err error
+		if messages, err = r.outboxRepo.GetPending(ctx, r.cfg.BatchSize); err != nil {
+			return fmt.Errorf("can't fetch outbox messages: %w", err)
+		}
+
+		ids := make([]int64, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		return r.outboxRepo.Claim(ctx, ids, time.Now().Add(r.cfg.ClaimTimeout))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// once a message fails, the rest of its aggregate waits for the retry
+	blocked := make(map[string]bool)
+	published := 0
+
+	for _, message := range messages {
+		key := fmt.Sprintf("%s:%d", message.AggregateType, message.AggregateID)
+		if blocked[key] {
+			// release the claim so it goes out right behind the retry
+			if err := r.outboxRepo.Claim(ctx, []int64{message.ID}, time.Now()); err != nil {
+				return published, err
+			}
+			continue
+		}
+
+		if err := r.publish(ctx, message); err != nil {
+			blocked[key] = true
+			next := time.Now().Add(r.backoff(message.Attempts))
+			if err := r.outboxRepo.MarkFailed(ctx, message.ID, next, err.Error()); err != nil {
+				return published, err
+			}
+			continue
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// publish hands message to every sink and marks it published.
+func (r *Relay) publish(ctx context.Context, message *models.OutboxMessage) (err error) {
+	// Delivery joins the trace of the request that recorded the event.
+	ctx, span := tracing.Start(tracing.WithTraceParent(ctx, message.TraceParent), "outbox publish "+message.EventType,
+		attribute.Int64("outbox.message_id", message.ID), attribute.String("outbox.aggregate_type", message.AggregateType))
+	defer func() { tracing.End(span, err) }()
+
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, message); err != nil {
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+	}
+
+	return repository.Transaction(ctx, r.db, func(ctx context.Context) error {
+		for _, sink := range r.dispatchSinks {
+			if err := sink.Publish(ctx, message); err != nil {
+				return fmt.Errorf("%s: %w", sink.Name(), err)
+			}
+		}
+		return r.outboxRepo.MarkPublished(ctx, message.ID)
+	})
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.BaseBackoff
+	for i := 0; i < attempts && delay < r.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.cfg.MaxBackoff {
+		delay = r.cfg.MaxBackoff
+	}
+	return delay
+}
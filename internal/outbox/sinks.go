@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/models"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LogSink writes messages to a logger. Useful in development.
+type LogSink struct {
+	logger *slog.Logger
+}
+
+func NewLogSink(logger *slog.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+func (s *LogSink) Publish(ctx context.Context, message *models.OutboxMessage) error {
+	s.logger.InfoContext(ctx, "Outbox event",
+		"message_id", message.ID, "event_type", message.EventType,
+		"aggregate_type", message.AggregateType, "aggregate_id", message.AggregateID,
+		"payload", message.Payload)
+	return nil
+}
+
+// RedisStreamSink appends messages to a Redis stream.
+type RedisStreamSink struct {
+	client *redis.Client
+	stream string
+}
+
+func NewRedisStreamSink(client *redis.Client, stream string) *RedisStreamSink {
+	return &RedisStreamSink{
+		client: client,
+		stream: stream,
+	}
+}
+
+func (s *RedisStreamSink) Name() string {
+	return "redis"
+}
+
+func (s *RedisStreamSink) Publish(ctx context.Context, message *models.OutboxMessage) error {
+	return s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		Values: map[string]interface{}{
+			"id":             message.ID,
+			"event_type":     message.EventType,
+			"aggregate_type": message.AggregateType,
+			"aggregate_id":   message.AggregateID,
+			"payload":        message.Payload,
+			"created_at":     message.CreatedAt.Format(time.RFC3339Nano),
+		},
+	}).Err()
+}
+
+// WebhookSink POSTs each message as JSON to a fixed URL. Any non-2xx response
+// counts as a failure and the message is retried.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+type webhookBody struct {
+	ID            int64           `json:"id"`
+	EventType     string          `json:"event_type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, message *models.OutboxMessage) error {
+	body, err := json.Marshal(webhookBody{
+		ID:            message.ID,
+		EventType:     message.EventType,
+		AggregateType: message.AggregateType,
+		AggregateID:   message.AggregateID,
+		Payload:       json.RawMessage(message.Payload),
+		CreatedAt:     message.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(message.ID, 10))
+	req.Header.Set("X-Event-Type", message.EventType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// retryPrimaryAfter is how long the fallback store keeps using memory after
+// the primary store fails, so an outage doesn't cost a timeout per request.
+const retryPrimaryAfter = 5 * time.Second
+
+type fallbackStore struct {
+	primary  Store
+	fallback Store
+	logger   *slog.Logger
+
+	mu        sync.Mutex
+	downUntil time.Time
+	down      bool
+}
+
+// NewFallbackStore uses primary, switching to fallback while primary is
+// failing. Counts are not carried across the switch, so clients briefly get
+// a fresh allowance.
+func NewFallbackStore(primary, fallback Store, logger *slog.Logger) Store {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &fallbackStore{primary: primary, fallback: fallback, logger: logger}
+}
+
+func (s *fallbackStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	skip := s.down && time.Now().Before(s.downUntil)
+	s.mu.Unlock()
+
+	if !skip {
+		res, err := s.primary.Allow(ctx, key, limit)
+		s.mu.Lock()
+		wasDown := s.down
+		if err == nil {
+			s.down = false
+		} else {
+			s.down = true
+			s.downUntil = time.Now().Add(retryPrimaryAfter)
+		}
+		s.mu.Unlock()
+
+		if err == nil {
+			if wasDown {
+				s.logger.Info("Rate limiter store recovered")
+			}
+			return res, nil
+		}
+		if !wasDown {
+			s.logger.Warn("Rate limiter store unavailable, limiting in memory", "error", err)
+		}
+	}
+
+	return s.fallback.Allow(ctx, key, limit)
+}
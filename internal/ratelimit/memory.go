@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store forgets idle keys.
+const sweepInterval = time.Minute
+
+type window struct {
+	hits   []time.Time // oldest first
+	expiry time.Time
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+// NewMemoryStore counts requests in this process only, so with several
+// instances each enforces the limit separately.
+func NewMemoryStore() Store {
+	return &memoryStore{windows: map[string]*window{}, lastSweep: time.Now()}
+}
+
+func (s *memoryStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, w := range s.windows {
+			if now.After(w.expiry) {
+				delete(s.windows, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok {
+		w = &window{}
+		s.windows[key] = w
+	}
+
+	cutoff := now.Add(-limit.Window)
+	kept := 0
+	for kept < len(w.hits) && !w.hits[kept].After(cutoff) {
+		kept++
+	}
+	w.hits = w.hits[kept:]
+
+	allowed := len(w.hits) < limit.Requests
+	if allowed {
+		w.hits = append(w.hits, now)
+	}
+	w.expiry = now.Add(limit.Window)
+
+	reset := limit.Window
+	if len(w.hits) > 0 {
+		reset = w.hits[0].Add(limit.Window).Sub(now)
+	}
+	return result(allowed, len(w.hits), limit, reset), nil
+}
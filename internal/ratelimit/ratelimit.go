@@ -0,0 +1,79 @@
+// Package ratelimit counts requests per client in a sliding window. Counts
+// live in Redis so every API instance shares them; if Redis is unreachable
+// each instance falls back to counting in its own memory.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Window. The zero Limit means unlimited.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Window <= 0
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// ParseLimit reads "<requests>/<window>", e.g. "10/1m" or "300/1h". "0" and
+// "" mean unlimited.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+
+	requests, window, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q is not <requests>/<window>", s)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid request count", s)
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: invalid window", s)
+	}
+	return Limit{Requests: n, Window: d}, nil
+}
+
+// Result is the outcome of one Allow call.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the oldest counted request leaves the window,
+	// i.e. until a denied client may retry.
+	Reset time.Duration
+}
+
+// Store counts a request against key and reports whether it is within limit.
+// Denied requests are not counted.
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+func result(allowed bool, count int, limit Limit, reset time.Duration) Result {
+	remaining := limit.Requests - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	if reset < 0 {
+		reset = 0
+	}
+	return Result{Allowed: allowed, Limit: limit.Requests, Remaining: remaining, Reset: reset}
+}
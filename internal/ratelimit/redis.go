@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript keeps one sorted-set member per counted request, scored
+// by its time in milliseconds. It drops members older than the window, adds
+// the new request if there is room, and returns {allowed, count, reset ms}.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call("PEXPIRE", KEYS[1], window)
+
+local reset = window
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`)
+
+type redisStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+// NewRedisStore counts requests in Redis under prefix+key.
+func NewRedisStore(client redis.Cmdable, prefix string) Store {
+	return &redisStore{client: client, prefix: prefix}
+}
+
+func (s *redisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now().UnixMilli()
+	member, err := requestID(now)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, err := slidingWindowScript.Run(ctx, s.client, []string{s.prefix + key},
+		now, limit.Window.Milliseconds(), limit.Requests, member).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("rate limit %s: %w", key, err)
+	}
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("rate limit %s: unexpected reply %v", key, values)
+	}
+
+	return result(values[0] == 1, int(values[1]), limit, time.Duration(values[2])*time.Millisecond), nil
+}
+
+// requestID makes set members unique when several requests share a
+// millisecond.
+func requestID(now int64) (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%s", now, hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,196 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const channelPrefix = "event-availability:"
+
+var (
+	ErrTooManySubscribers = errors.New("too many subscribers")
+	ErrHubClosed          = errors.New("availability hub closed")
+)
+
+type Availability struct {
+	EventID     int       `json:"event_id"`
+	TicketsLeft int       `json:"tickets_left"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Subscription receives availability updates for one event. C holds at most
+// one pending update: a slow reader skips intermediate values and only sees
+// the latest, so it can never hold up the hub. C is closed when the hub stops.
+type Subscription struct {
+	EventID int
+	C       <-chan Availability
+
+	ch   chan Availability
+	hub  *Hub
+	once sync.Once
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.remove(s)
+	})
+}
+
+// Hub fans availability updates out to the SSE subscribers of this instance.
+// Updates are published through Redis so that every instance sees changes
+// made on any other one.
+type Hub struct {
+	client         *redis.Client
+	maxSubscribers int
+	logger         *slog.Logger
+
+	mu          sync.Mutex
+	closed      bool
+	count       int
+	subscribers map[int]map[*Subscription]struct{}
+}
+
+func NewHub(client *redis.Client, maxSubscribers int, logger *slog.Logger) *Hub {
+	return &Hub{
+		client:         client,
+		maxSubscribers: maxSubscribers,
+		logger:         logger,
+		subscribers:    make(map[int]map[*Subscription]struct{}),
+	}
+}
+
+func (h *Hub) Publish(ctx context.Context, update Availability) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	return h.client.Publish(ctx, channelPrefix+strconv.Itoa(update.EventID), data).Err()
+}
+
+func (h *Hub) Subscribe(eventID int) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+	if h.maxSubscribers > 0 && h.count >= h.maxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+
+	ch := make(chan Availability, 1)
+	sub := &Subscription{EventID: eventID, C: ch, ch: ch, hub: h}
+
+	if h.subscribers[eventID] == nil {
+		h.subscribers[eventID] = make(map[*Subscription]struct{})
+	}
+	h.subscribers[eventID][sub] = struct{}{}
+	h.count++
+
+	return sub, nil
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.subscribers[sub.EventID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.EventID)
+	}
+	h.count--
+}
+
+// SubscriberCount returns the number of open subscriptions on this instance.
+func (h *Hub) SubscriberCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Run listens on Redis until ctx is cancelled, resubscribing after
+// connection errors. On return every subscription is closed so open streams
+// end and don't hold up server shutdown.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.closeAll()
+
+	h.logger.InfoContext(ctx, "Availability hub started")
+
+	for ctx.Err() == nil {
+		if err := h.listen(ctx); err != nil && ctx.Err() == nil {
+			h.logger.WarnContext(ctx, "Availability hub disconnected", "error", err)
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+			}
+		}
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			close(sub.ch)
+		}
+	}
+	h.subscribers = make(map[int]map[*Subscription]struct{})
+	h.count = 0
+	h.closed = true
+}
+
+func (h *Hub) listen(ctx context.Context) error {
+	pubsub := h.client.PSubscribe(ctx, channelPrefix+"*")
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+
+		var update Availability
+		if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil {
+			h.logger.WarnContext(ctx, "Ignoring malformed availability update", "channel", msg.Channel, "error", err)
+			continue
+		}
+		if id, err := strconv.Atoi(strings.TrimPrefix(msg.Channel, channelPrefix)); err != nil || id != update.EventID {
+			h.logger.WarnContext(ctx, "Ignoring availability update with mismatched channel", "channel", msg.Channel)
+			continue
+		}
+
+		h.broadcast(update)
+	}
+}
+
+func (h *Hub) broadcast(update Availability) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers[update.EventID] {
+		// replace whatever the subscriber hasn't read yet with the newer value
+		select {
+		case <-sub.ch:
+		default:
+		}
+		sub.ch <- update
+	}
+}
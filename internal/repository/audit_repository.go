@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"event-booking-be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// defaultAuditLimit bounds List when the filter sets no limit.
+const defaultAuditLimit = 100
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	return dbWithContext(ctx, r.db).Create(entry).Error
+}
+
+// List returns matching entries, newest first.
+func (r *auditRepository) List(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	query := dbWithContext(ctx, r.db).Order("created_at DESC, id DESC")
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	var entries []*models.AuditLog
+	err := query.Limit(limit).Find(&entries).Error
+	return entries, err
+}
@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/audit"
 	"event-booking-be/internal/models"
 	"fmt"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -18,21 +22,26 @@ func NewBookingRepository(db *gorm.DB) BookingRepository {
 }
 
 func (r *bookingRepository) Create(ctx context.Context, booking *models.Booking) error {
-	return r.db.WithContext(ctx).Create(booking).Error
+	return Transaction(ctx, r.db, func(ctx context.Context) error {
+		if err := dbWithContext(ctx, r.db).Create(booking).Error; err != nil {
+			return err
+		}
+		return r.recordStatusChange(ctx, booking.ID, "", booking.Status, models.BookingReasonCreated)
+	})
 }
 
 func (r *bookingRepository) GetByID(ctx context.Context, id int) (*models.Booking, error) {
 	var booking models.Booking
-	err := r.db.WithContext(ctx).First(&booking, id).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("booking not found")
+	err := dbWithContext(ctx, r.db).First(&booking, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrBookingNotFound
 	}
 	return &booking, err
 }
 
 func (r *bookingRepository) GetByUserID(ctx context.Context, userID int) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	err := r.db.WithContext(ctx).
+	err := dbWithContext(ctx, r.db).
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&bookings).Error
@@ -41,34 +50,105 @@ func (r *bookingRepository) GetByUserID(ctx context.Context, userID int) ([]*mod
 
 func (r *bookingRepository) GetByEventID(ctx context.Context, eventID int) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	err := r.db.WithContext(ctx).
+	err := dbWithContext(ctx, r.db).
 		Where("event_id = ?", eventID).
 		Order("created_at DESC").
 		Find(&bookings).Error
 	return bookings, err
 }
 
-func (r *bookingRepository) UpdateStatus(ctx context.Context, id int, status models.BookingStatus) error {
+// TransitionStatus moves a booking to status to, but only if it is still in
+// status from, and appends the change, with reason and the actor carried by
+// ctx, to its status history. The check and the write are one statement, so of
+// two racing transitions out of the same status exactly one succeeds; the
+// loser gets an error naming the status the booking ended up in.
+func (r *bookingRepository) TransitionStatus(ctx context.Context, id int, from, to models.BookingStatus, reason string) error {
 	updates := map[string]interface{}{
-		"status": status,
+		"status": to,
 	}
-	
-	if status == models.BookingStatusConfirmed {
+
+	switch to {
+	case models.BookingStatusConfirmed:
 		updates["confirmed_at"] = time.Now()
-	} else if status == models.BookingStatusCancelled {
+	case models.BookingStatusCancelled:
 		updates["cancelled_at"] = time.Now()
+	case models.BookingStatusExpired:
+		updates["expired_at"] = time.Now()
+	}
+
+	return Transaction(ctx, r.db, func(ctx context.Context) error {
+		result := dbWithContext(ctx, r.db).Model(&models.Booking{}).
+			Where("id = ? AND status = ?", id, from).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			return r.recordStatusChange(ctx, id, from, to, reason)
+		}
+
+		current, err := r.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return apperrors.BookingState(string(current.Status))
+	})
+}
+
+// CheckIn marks a confirmed booking as checked in at at. Like
+// TransitionStatus it checks and writes in one statement, so a booking
+// scanned at two doors at once is let in only once.
+func (r *bookingRepository) CheckIn(ctx context.Context, id int, at time.Time) error {
+	result := dbWithContext(ctx, r.db).Model(&models.Booking{}).
+		Where("id = ? AND status = ? AND checked_in_at IS NULL", id, models.BookingStatusConfirmed).
+		Update("checked_in_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.Status != models.BookingStatusConfirmed {
+		return apperrors.ErrNotConfirmed
+	}
+	return apperrors.ErrAlreadyCheckedIn
+}
+
+func (r *bookingRepository) recordStatusChange(ctx context.Context, id int, from, to models.BookingStatus, reason string) error {
+	actor := audit.ActorFrom(ctx)
+	change := &models.BookingStatusChange{
+		BookingID:  id,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		ActorName:  actor.Name,
 	}
-	
-	result := r.db.WithContext(ctx).Model(&models.Booking{}).Where("id = ?", id).Updates(updates)
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("booking not found")
+	if err := dbWithContext(ctx, r.db).Create(change).Error; err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
 	}
-	return result.Error
+	return nil
+}
+
+// GetStatusHistory returns a booking's status changes, oldest first.
+func (r *bookingRepository) GetStatusHistory(ctx context.Context, id int) ([]*models.BookingStatusChange, error) {
+	var history []*models.BookingStatusChange
+	err := dbWithContext(ctx, r.db).
+		Where("booking_id = ?", id).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error
+	return history, err
 }
 
 func (r *bookingRepository) GetExpiredPending(ctx context.Context) ([]*models.Booking, error) {
 	var bookings []*models.Booking
-	err := r.db.WithContext(ctx).
+	err := dbWithContext(ctx, r.db).
 		Where("status = ? AND expires_at < ?", models.BookingStatusPending, time.Now()).
 		Order("expires_at ASC").
 		Find(&bookings).Error
@@ -77,22 +157,183 @@ func (r *bookingRepository) GetExpiredPending(ctx context.Context) ([]*models.Bo
 
 func (r *bookingRepository) GetWithDetails(ctx context.Context, id int) (*models.BookingWithDetails, error) {
 	var booking models.BookingWithDetails
-	err := r.db.WithContext(ctx).
+	err := dbWithContext(ctx, r.db).
 		Table("bookings b").
 		Select(`
-			b.id, b.user_id, b.event_id, b.ticket_count, b.total_price, b.status,
-			b.expires_at, b.confirmed_at, b.cancelled_at, b.created_at, b.updated_at,
+			b.id, b.user_id, b.event_id, b.ticket_count, b.total_price, b.status, b.payment_instrument, b.ticket_type,
+			b.expires_at, b.confirmed_at, b.cancelled_at, b.expired_at, b.checked_in_at, b.created_at, b.updated_at,
 			u.name as user_name, u.email as user_email, e.name as event_name, e.date_time as event_date_time
 		`).
 		Joins("JOIN users u ON b.user_id = u.id").
 		Joins("JOIN events e ON b.event_id = e.id").
 		Where("b.id = ? AND b.deleted_at IS NULL", id).
 		Scan(&booking).Error
-	
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("booking not found")
+	if err != nil {
+		return nil, err
 	}
-	return &booking, err
+	if booking.ID == 0 {
+		return nil, apperrors.ErrBookingNotFound
+	}
+	return &booking, nil
+}
+
+func (r *bookingRepository) List(ctx context.Context, filter models.BookingFilter) ([]*models.Booking, error) {
+	query := dbWithContext(ctx, r.db).Order("created_at DESC")
+	if filter.EventID != 0 {
+		query = query.Where("event_id = ?", filter.EventID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var bookings []*models.Booking
+	err := query.Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *bookingRepository) GetAttendees(ctx context.Context, eventID int, statuses []models.BookingStatus) ([]*models.Attendee, error) {
+	var attendees []*models.Attendee
+	err := r.StreamAttendees(ctx, eventID, statuses, func(attendee *models.Attendee) error {
+		attendees = append(attendees, attendee)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attendees, nil
+}
+
+// StreamAttendees calls fn for each attendee in booking order, reading rows
+// off the cursor one at a time instead of loading the whole list.
+func (r *bookingRepository) StreamAttendees(ctx context.Context, eventID int, statuses []models.BookingStatus, fn func(*models.Attendee) error) error {
+	db := dbWithContext(ctx, r.db)
+	rows, err := db.
+		Table("bookings b").
+		Select(`
+			b.id as booking_id, b.user_id, b.ticket_count, b.ticket_type, b.status, b.created_at as booked_at,
+			b.checked_in_at, u.name, u.email
+		`).
+		Joins("JOIN users u ON b.user_id = u.id").
+		Where("b.event_id = ? AND b.status IN ? AND b.deleted_at IS NULL", eventID, statuses).
+		Order("b.created_at ASC, b.id ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attendee := &models.Attendee{}
+		if err := db.ScanRows(rows, attendee); err != nil {
+			return err
+		}
+		attendee.Reference = models.BookingReference(attendee.BookingID)
+		if err := fn(attendee); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// GetConfirmedWithEvents returns the user's confirmed bookings with their
+// events, deleted events included, in event date order.
+func (r *bookingRepository) GetConfirmedWithEvents(ctx context.Context, userID int) ([]*models.Booking, error) {
+	var bookings []*models.Booking
+	err := dbWithContext(ctx, r.db).
+		Preload("Event", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Joins("JOIN events e ON e.id = bookings.event_id").
+		Where("bookings.user_id = ? AND bookings.status = ?", userID, models.BookingStatusConfirmed).
+		Order("e.date_time ASC, bookings.id ASC").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// SumHeldTickets counts the tickets taken out of an event's inventory: those
+// of pending and confirmed bookings.
+func (r *bookingRepository) SumHeldTickets(ctx context.Context, eventID int) (int, error) {
+	var held int
+	err := r.heldTickets(ctx, eventID).Scan(&held).Error
+	return held, err
+}
+
+// SumHeldTicketsByUser counts the tickets a user holds for an event.
+func (r *bookingRepository) SumHeldTicketsByUser(ctx context.Context, eventID, userID int) (int, error) {
+	var held int
+	err := r.heldTickets(ctx, eventID).
+		Where("user_id = ?", userID).
+		Scan(&held).Error
+	return held, err
+}
+
+// SumHeldTicketsByPaymentInstrument counts the tickets held for an event by
+// bookings paid with instrument, whoever made them.
+func (r *bookingRepository) SumHeldTicketsByPaymentInstrument(ctx context.Context, eventID int, instrument string) (int, error) {
+	var held int
+	err := r.heldTickets(ctx, eventID).
+		Where("payment_instrument = ?", instrument).
+		Scan(&held).Error
+	return held, err
+}
+
+// SumHeldTicketsByEmailDomain counts the tickets held for an event by every
+// user whose email is at the same domain as userID's.
+func (r *bookingRepository) SumHeldTicketsByEmailDomain(ctx context.Context, eventID, userID int) (int, error) {
+	var user models.User
+	err := dbWithContext(ctx, r.db).Select("email").First(&user, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, apperrors.ErrUserNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	at := strings.LastIndex(user.Email, "@")
+	if at < 0 {
+		return 0, nil
+	}
+	domain := strings.ToLower(user.Email[at:])
+
+	var held int
+	err = r.heldTickets(ctx, eventID).
+		Where("user_id IN (?)", dbWithContext(ctx, r.db).Model(&models.User{}).
+			Select("id").
+			Where(`LOWER(email) LIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(domain))).
+		Scan(&held).Error
+	return held, err
+}
+
+// likeEscaper makes a string match itself literally in a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetActivity returns the bookings of eventIDs that were created, confirmed,
+// cancelled or expired in [from, to).
+func (r *bookingRepository) GetActivity(ctx context.Context, eventIDs []int, from, to time.Time) ([]*models.BookingActivity, error) {
+	var activity []*models.BookingActivity
+	err := dbWithContext(ctx, r.db).
+		Model(&models.Booking{}).
+		Select("event_id, status, ticket_type, ticket_count, total_price, created_at, confirmed_at, cancelled_at, expired_at").
+		Where("event_id IN ?", eventIDs).
+		// a booking has at most one of the three outcome timestamps
+		Where("created_at < ? AND COALESCE(confirmed_at, cancelled_at, expired_at, created_at) >= ?", to, from).
+		Order("created_at ASC").
+		Scan(&activity).Error
+	return activity, err
+}
+
+// heldTickets sums ticket_count over an event's pending and confirmed
+// bookings; callers narrow it further.
+func (r *bookingRepository) heldTickets(ctx context.Context, eventID int) *gorm.DB {
+	return dbWithContext(ctx, r.db).
+		Model(&models.Booking{}).
+		Select("COALESCE(SUM(ticket_count), 0)").
+		Where("event_id = ? AND status IN ?", eventID,
+			[]models.BookingStatus{models.BookingStatusPending, models.BookingStatusConfirmed})
 }
 
 func (r *bookingRepository) GetStatsByEventID(ctx context.Context, eventID int) (*models.EventStatistics, error) {
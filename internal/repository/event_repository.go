@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/metrics"
 	"event-booking-be/internal/models"
-	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -18,69 +21,158 @@ func NewEventRepository(db *gorm.DB) EventRepository {
 }
 
 func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
-	return r.db.WithContext(ctx).Create(event).Error
+	if event.Capacity == 0 {
+		event.Capacity = event.TotalTickets
+	}
+	return dbWithContext(ctx, r.db).Create(event).Error
 }
 
 func (r *eventRepository) GetByID(ctx context.Context, id int) (*models.Event, error) {
 	var event models.Event
-	err := r.db.WithContext(ctx).First(&event, id).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("event not found")
+	err := dbWithContext(ctx, r.db).Preload("TicketTypes", orderTicketTypes).First(&event, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrEventNotFound
 	}
 	return &event, err
 }
 
 func (r *eventRepository) GetAll(ctx context.Context) ([]*models.Event, error) {
 	var events []*models.Event
-	err := r.db.WithContext(ctx).Order("date_time ASC").Find(&events).Error
+	err := dbWithContext(ctx, r.db).Preload("TicketTypes", orderTicketTypes).Order("date_time ASC").Find(&events).Error
+	return events, err
+}
+
+func (r *eventRepository) GetByOrganizer(ctx context.Context, organizerID int) ([]*models.Event, error) {
+	var events []*models.Event
+	err := dbWithContext(ctx, r.db).Preload("TicketTypes", orderTicketTypes).
+		Where("organizer_id = ?", organizerID).Order("date_time ASC").Find(&events).Error
+	return events, err
+}
+
+func orderTicketTypes(db *gorm.DB) *gorm.DB {
+	return db.Order("id ASC")
+}
+
+// GetTicketType returns the event's ticket type with the given name.
+func (r *eventRepository) GetTicketType(ctx context.Context, eventID int, name string) (*models.TicketType, error) {
+	var ticketType models.TicketType
+	err := dbWithContext(ctx, r.db).Where("event_id = ? AND name = ?", eventID, name).First(&ticketType).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrUnknownTicketType.Withf("event %d has no ticket type %q", eventID, name)
+	}
+	return &ticketType, err
+}
+
+// ReplaceTicketTypes swaps the event's ticket types for ticketTypes. Call it
+// inside a transaction so bookings never see the event without any.
+func (r *eventRepository) ReplaceTicketTypes(ctx context.Context, eventID int, ticketTypes []models.TicketType) error {
+	db := dbWithContext(ctx, r.db)
+	if err := db.Where("event_id = ?", eventID).Delete(&models.TicketType{}).Error; err != nil {
+		return err
+	}
+	if len(ticketTypes) == 0 {
+		return nil
+	}
+	for i := range ticketTypes {
+		ticketTypes[i].ID = 0
+		ticketTypes[i].EventID = eventID
+	}
+	return db.Create(&ticketTypes).Error
+}
+
+// GetUpcoming returns the events starting at or after since, in date order,
+// including deleted ones so feeds can show them as cancelled.
+func (r *eventRepository) GetUpcoming(ctx context.Context, since time.Time) ([]*models.Event, error) {
+	var events []*models.Event
+	err := dbWithContext(ctx, r.db).Unscoped().
+		Where("date_time >= ?", since).
+		Order("date_time ASC").
+		Find(&events).Error
 	return events, err
 }
 
-func (r *eventRepository) Update(ctx context.Context, id int, event *models.Event) error {
-	result := r.db.WithContext(ctx).Model(&models.Event{}).Where("id = ?", id).Updates(event)
+// GetByExternalRef returns the organizer's event with the given external
+// reference.
+func (r *eventRepository) GetByExternalRef(ctx context.Context, organizerID int, ref string) (*models.Event, error) {
+	var event models.Event
+	err := dbWithContext(ctx, r.db).Where("organizer_id = ? AND external_ref = ?", organizerID, ref).First(&event).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrEventNotFound
+	}
+	return &event, err
+}
+
+// Update writes the given columns of event, zero values included, so a limit
+// can be set back to 0 (none). Columns left out keep what's in the database.
+func (r *eventRepository) Update(ctx context.Context, id int, event *models.Event, columns []string) error {
+	event.UpdatedAt = time.Now()
+	result := dbWithContext(ctx, r.db).Model(&models.Event{}).Where("id = ?", id).
+		Select(append(columns, "updated_at")).Updates(event)
+	if result.Error != nil {
+		return result.Error
+	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("event not found")
+		return apperrors.ErrEventNotFound
 	}
-	return result.Error
+	return nil
 }
 
 func (r *eventRepository) Delete(ctx context.Context, id int) error {
-	result := r.db.WithContext(ctx).Delete(&models.Event{}, id)
+	result := dbWithContext(ctx, r.db).Delete(&models.Event{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("event not found")
+		return apperrors.ErrEventNotFound
 	}
-	return result.Error
+	return nil
 }
 
 func (r *eventRepository) GetAvailableTickets(ctx context.Context, eventID int) (int, error) {
 	var event models.Event
-	err := r.db.WithContext(ctx).Select("total_tickets").First(&event, eventID).Error
+	err := dbWithContext(ctx, r.db).Select("total_tickets").First(&event, eventID).Error
 	return event.TotalTickets, err
 }
 
 func (r *eventRepository) DecrementTickets(ctx context.Context, eventID int, count int) error {
-	result := r.db.WithContext(ctx).
+	result := dbWithContext(ctx, r.db).
 		Model(&models.Event{}).
 		Where("id = ? AND total_tickets >= ?", eventID, count).
 		UpdateColumn("total_tickets", gorm.Expr("total_tickets - ?", count))
-	
+	if result.Error != nil {
+		return result.Error
+	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("not enough tickets available")
+		return apperrors.ErrNotEnoughTickets
 	}
-	return result.Error
+	return nil
 }
 
 func (r *eventRepository) IncrementTickets(ctx context.Context, eventID int, count int) error {
-	return r.db.WithContext(ctx).
+	return dbWithContext(ctx, r.db).
 		Model(&models.Event{}).
 		Where("id = ?", eventID).
 		UpdateColumn("total_tickets", gorm.Expr("total_tickets + ?", count)).Error
 }
 
+func (r *eventRepository) SetAvailableTickets(ctx context.Context, eventID int, count int) error {
+	result := dbWithContext(ctx, r.db).
+		Model(&models.Event{}).
+		Where("id = ?", eventID).
+		UpdateColumn("total_tickets", count)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrEventNotFound
+	}
+	return nil
+}
+
 func (r *eventRepository) GetStatsByEventID(ctx context.Context, eventID int) (*models.EventStatistics, error) {
 	var stats models.EventStatistics
 	
-	err := r.db.WithContext(ctx).
+	err := dbWithContext(ctx, r.db).
 		Table("events e").
 		Select(`
 			e.id as event_id,
@@ -95,18 +187,26 @@ func (r *eventRepository) GetStatsByEventID(ctx context.Context, eventID int) (*
 		Where("e.id = ?", eventID).
 		Group("e.id, e.name, e.total_tickets").
 		Scan(&stats).Error
-	
-	return &stats, err
+	if err != nil {
+		return nil, err
+	}
+	if stats.EventID == 0 {
+		return nil, apperrors.ErrEventNotFound
+	}
+	return &stats, nil
 }
 
 func (r *eventRepository) LockForUpdate(ctx context.Context, eventID int) (*models.Event, error) {
 	var event models.Event
-	err := r.db.WithContext(ctx).
+	start := time.Now()
+	err := dbWithContext(ctx, r.db).
 		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("TicketTypes", orderTicketTypes).
 		First(&event, eventID).Error
+	metrics.ObserveLockWait(time.Since(start))
 	
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("event not found")
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrEventNotFound
 	}
 	return &event, err
 }
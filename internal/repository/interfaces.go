@@ -3,19 +3,26 @@ package repository
 import (
 	"context"
 	"event-booking-be/internal/models"
+	"time"
 )
 
 type EventRepository interface {
 	Create(ctx context.Context, event *models.Event) error
 	GetByID(ctx context.Context, id int) (*models.Event, error)
 	GetAll(ctx context.Context) ([]*models.Event, error)
-	Update(ctx context.Context, id int, event *models.Event) error
+	GetByOrganizer(ctx context.Context, organizerID int) ([]*models.Event, error)
+	GetByExternalRef(ctx context.Context, organizerID int, ref string) (*models.Event, error)
+	GetUpcoming(ctx context.Context, since time.Time) ([]*models.Event, error)
+	Update(ctx context.Context, id int, event *models.Event, columns []string) error
 	Delete(ctx context.Context, id int) error
 	GetAvailableTickets(ctx context.Context, eventID int) (int, error)
 	DecrementTickets(ctx context.Context, eventID int, count int) error
 	IncrementTickets(ctx context.Context, eventID int, count int) error
+	SetAvailableTickets(ctx context.Context, eventID int, count int) error
 	GetStatsByEventID(ctx context.Context, eventID int) (*models.EventStatistics, error)
 	LockForUpdate(ctx context.Context, eventID int) (*models.Event, error)
+	GetTicketType(ctx context.Context, eventID int, name string) (*models.TicketType, error)
+	ReplaceTicketTypes(ctx context.Context, eventID int, ticketTypes []models.TicketType) error
 }
 
 type UserRepository interface {
@@ -23,6 +30,9 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id int) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	GetAll(ctx context.Context) ([]*models.User, error)
+	UpdateRole(ctx context.Context, id int, role string) error
+	GetByCalendarTokenHash(ctx context.Context, hash string) (*models.User, error)
+	UpdateCalendarTokenHash(ctx context.Context, id int, hash string) error
 }
 
 type BookingRepository interface {
@@ -30,7 +40,67 @@ type BookingRepository interface {
 	GetByID(ctx context.Context, id int) (*models.Booking, error)
 	GetByUserID(ctx context.Context, userID int) ([]*models.Booking, error)
 	GetByEventID(ctx context.Context, eventID int) ([]*models.Booking, error)
-	UpdateStatus(ctx context.Context, id int, status models.BookingStatus) error
+	TransitionStatus(ctx context.Context, id int, from, to models.BookingStatus, reason string) error
+	GetStatusHistory(ctx context.Context, id int) ([]*models.BookingStatusChange, error)
+	CheckIn(ctx context.Context, id int, at time.Time) error
 	GetExpiredPending(ctx context.Context) ([]*models.Booking, error)
 	GetWithDetails(ctx context.Context, id int) (*models.BookingWithDetails, error)
+	List(ctx context.Context, filter models.BookingFilter) ([]*models.Booking, error)
+	GetAttendees(ctx context.Context, eventID int, statuses []models.BookingStatus) ([]*models.Attendee, error)
+	StreamAttendees(ctx context.Context, eventID int, statuses []models.BookingStatus, fn func(*models.Attendee) error) error
+	GetConfirmedWithEvents(ctx context.Context, userID int) ([]*models.Booking, error)
+	SumHeldTickets(ctx context.Context, eventID int) (int, error)
+	SumHeldTicketsByUser(ctx context.Context, eventID, userID int) (int, error)
+	SumHeldTicketsByPaymentInstrument(ctx context.Context, eventID int, instrument string) (int, error)
+	SumHeldTicketsByEmailDomain(ctx context.Context, eventID, userID int) (int, error)
+	GetActivity(ctx context.Context, eventIDs []int, from, to time.Time) ([]*models.BookingActivity, error)
+}
+
+type OutboxRepository interface {
+	Create(ctx context.Context, message *models.OutboxMessage) error
+	GetPending(ctx context.Context, limit int) ([]*models.OutboxMessage, error)
+	Claim(ctx context.Context, ids []int64, until time.Time) error
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id int) (*models.WebhookSubscription, error)
+	GetSubscriptionsByOrganizer(ctx context.Context, organizerID int) ([]*models.WebhookSubscription, error)
+	GetActiveSubscriptionsByOrganizer(ctx context.Context, organizerID int) ([]*models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int) error
+	RecordSubscriptionSuccess(ctx context.Context, id int) error
+	RecordSubscriptionFailure(ctx context.Context, id int, disableAfter int) error
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDueDeliveries(ctx context.Context, limit int) ([]*models.WebhookDelivery, error)
+	ClaimDeliveries(ctx context.Context, ids []int64, until time.Time) error
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveriesBySubscription(ctx context.Context, subscriptionID int, limit int) ([]*models.WebhookDelivery, error)
+}
+
+type ReminderRepository interface {
+	CreateMany(ctx context.Context, reminders []*models.Reminder) error
+	GetByEventID(ctx context.Context, eventID int) ([]*models.Reminder, error)
+	Reschedule(ctx context.Context, reminder *models.Reminder, sendAt time.Time) (bool, error)
+	CancelByBookingID(ctx context.Context, bookingID int) error
+	GetDue(ctx context.Context, limit int) ([]*models.Reminder, error)
+	Claim(ctx context.Context, reminder *models.Reminder, lease time.Duration) (bool, error)
+	SaveOutcome(ctx context.Context, reminder *models.Reminder) (bool, error)
+}
+
+type JobRepository interface {
+	Create(ctx context.Context, job *models.Job) error
+	GetRunnable(ctx context.Context, limit int) ([]*models.Job, error)
+	Claim(ctx context.Context, job *models.Job, workerID string, visibility time.Duration) (bool, error)
+	Complete(ctx context.Context, job *models.Job) error
+	Retry(ctx context.Context, job *models.Job, runAt time.Time, lastError string) error
+	Fail(ctx context.Context, job *models.Job, lastError string) error
+	CountRunnable(ctx context.Context) (int64, error)
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	List(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error)
 }
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"event-booking-be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+// Create ignores a job whose UniqueKey is already taken, so scheduling the
+// same work twice is harmless.
+func (r *jobRepository) Create(ctx context.Context, job *models.Job) error {
+	if job.Status == "" {
+		job.Status = models.JobStatusPending
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = 5
+	}
+	return dbWithContext(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(job).Error
+}
+
+func (r *jobRepository) runnable(ctx context.Context) *gorm.DB {
+	now := time.Now()
+	return dbWithContext(ctx, r.db).
+		Model(&models.Job{}).
+		Where("run_at <= ?", now).
+		Where("status = ? OR (status = ? AND locked_until < ?)",
+			models.JobStatusPending, models.JobStatusRunning, now)
+}
+
+// GetRunnable returns due jobs, including running ones whose lock has lapsed.
+func (r *jobRepository) GetRunnable(ctx context.Context, limit int) ([]*models.Job, error) {
+	var jobs []*models.Job
+	err := r.runnable(ctx).
+		Order("run_at ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *jobRepository) CountRunnable(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.runnable(ctx).Count(&count).Error
+	return count, err
+}
+
+// Claim locks the job for workerID until the visibility timeout passes. It's a
+// compare-and-set on the status and attempt count read by GetRunnable, so of
+// several workers racing for one job exactly one wins.
+func (r *jobRepository) Claim(ctx context.Context, job *models.Job, workerID string, visibility time.Duration) (bool, error) {
+	lockedUntil := time.Now().Add(visibility)
+
+	result := dbWithContext(ctx, r.db).
+		Model(&models.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, job.Status, job.Attempts).
+		Updates(map[string]interface{}{
+			"status":       models.JobStatusRunning,
+			"locked_by":    workerID,
+			"locked_until": lockedUntil,
+			"attempts":     gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	job.Status = models.JobStatusRunning
+	job.LockedBy = workerID
+	job.LockedUntil = &lockedUntil
+	job.Attempts++
+	return true, nil
+}
+
+func (r *jobRepository) Complete(ctx context.Context, job *models.Job) error {
+	return r.release(ctx, job, map[string]interface{}{
+		"status":       models.JobStatusDone,
+		"completed_at": time.Now(),
+		"last_error":   "",
+	})
+}
+
+func (r *jobRepository) Retry(ctx context.Context, job *models.Job, runAt time.Time, lastError string) error {
+	return r.release(ctx, job, map[string]interface{}{
+		"status":     models.JobStatusPending,
+		"run_at":     runAt,
+		"last_error": lastError,
+	})
+}
+
+func (r *jobRepository) Fail(ctx context.Context, job *models.Job, lastError string) error {
+	return r.release(ctx, job, map[string]interface{}{
+		"status":     models.JobStatusFailed,
+		"last_error": lastError,
+	})
+}
+
+// release only touches the job while this worker still holds it; if the lock
+// lapsed and someone else claimed the job, their outcome wins.
+func (r *jobRepository) release(ctx context.Context, job *models.Job, updates map[string]interface{}) error {
+	updates["locked_by"] = ""
+	updates["locked_until"] = nil
+
+	return dbWithContext(ctx, r.db).
+		Model(&models.Job{}).
+		Where("id = ? AND locked_by = ? AND attempts = ?", job.ID, job.LockedBy, job.Attempts).
+		Updates(updates).Error
+}
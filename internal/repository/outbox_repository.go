@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"event-booking-be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, message *models.OutboxMessage) error {
+	if message.NextAttemptAt.IsZero() {
+		message.NextAttemptAt = time.Now()
+	}
+	return dbWithContext(ctx, r.db).Create(message).Error
+}
+
+// GetPending returns unpublished messages that are due, oldest first. A message
+// is only returned once every earlier message of the same aggregate has been
+// published, which keeps delivery ordered per aggregate. Rows are locked with
+// SKIP LOCKED so concurrent relays don't pick up the same batch.
+func (r *outboxRepository) GetPending(ctx context.Context, limit int) ([]*models.OutboxMessage, error) {
+	var messages []*models.OutboxMessage
+	err := dbWithContext(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("published_at IS NULL AND next_attempt_at <= ?", time.Now()).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM outbox_messages prev
+			WHERE prev.aggregate_type = outbox_messages.aggregate_type
+			AND prev.aggregate_id = outbox_messages.aggregate_id
+			AND prev.published_at IS NULL
+			AND prev.id < outbox_messages.id
+		)`).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
+}
+
+// Claim holds messages returned by GetPending until the given time, so they
+// aren't picked up again while their sinks run outside the fetching
+// transaction. A relay that dies mid-batch leaves them to be retried once the
+// claim runs out.
+func (r *outboxRepository) Claim(ctx context.Context, ids []int64, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return dbWithContext(ctx, r.db).
+		Model(&models.OutboxMessage{}).
+		Where("id IN ?", ids).
+		Update("next_attempt_at", until).Error
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id int64) error {
+	return dbWithContext(ctx, r.db).
+		Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"published_at": time.Now(),
+			"last_error":   "",
+		}).Error
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	return dbWithContext(ctx, r.db).
+		Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}).Error
+}
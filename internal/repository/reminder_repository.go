@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"event-booking-be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// CreateMany skips reminders that already exist for the same booking and
+// offset, so scheduling twice is harmless.
+func (r *reminderRepository) CreateMany(ctx context.Context, reminders []*models.Reminder) error {
+	if len(reminders) == 0 {
+		return nil
+	}
+	return dbWithContext(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&reminders).Error
+}
+
+func (r *reminderRepository) GetByEventID(ctx context.Context, eventID int) ([]*models.Reminder, error) {
+	var reminders []*models.Reminder
+	err := dbWithContext(ctx, r.db).
+		Where("event_id = ?", eventID).
+		Order("id ASC").
+		Find(&reminders).Error
+	return reminders, err
+}
+
+// Reschedule makes the reminder pending again at sendAt. A reminder that was
+// sent or being sent starts over with no attempts. Like Claim it's a
+// compare-and-set on the row as read, so it returns false if the reminder was
+// claimed or finished in the meantime.
+func (r *reminderRepository) Reschedule(ctx context.Context, reminder *models.Reminder, sendAt time.Time) (bool, error) {
+	updates := map[string]interface{}{
+		"status":        models.ReminderStatusPending,
+		"send_at":       sendAt,
+		"claimed_until": nil,
+	}
+	if reminder.Status != models.ReminderStatusPending {
+		updates["attempts"] = 0
+		updates["sent_at"] = nil
+	}
+
+	result := dbWithContext(ctx, r.db).
+		Model(&models.Reminder{}).
+		Where("id = ? AND status = ? AND attempts = ?", reminder.ID, reminder.Status, reminder.Attempts).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	if reminder.Status != models.ReminderStatusPending {
+		reminder.Attempts = 0
+		reminder.SentAt = nil
+	}
+	reminder.Status = models.ReminderStatusPending
+	reminder.SendAt = sendAt
+	reminder.ClaimedUntil = nil
+	return true, nil
+}
+
+func (r *reminderRepository) CancelByBookingID(ctx context.Context, bookingID int) error {
+	return dbWithContext(ctx, r.db).
+		Model(&models.Reminder{}).
+		Where("booking_id = ? AND status IN ?", bookingID, []models.ReminderStatus{
+			models.ReminderStatusPending,
+			models.ReminderStatusSending,
+		}).
+		Update("status", models.ReminderStatusCancelled).Error
+}
+
+// GetDue returns pending reminders whose time has come, plus ones stuck in
+// SENDING whose claim has lapsed (the claiming instance died mid-send).
+func (r *reminderRepository) GetDue(ctx context.Context, limit int) ([]*models.Reminder, error) {
+	now := time.Now()
+
+	var reminders []*models.Reminder
+	err := dbWithContext(ctx, r.db).
+		Where("send_at <= ?", now).
+		Where("(status = ? AND (claimed_until IS NULL OR claimed_until < ?)) OR (status = ? AND claimed_until < ?)",
+			models.ReminderStatusPending, now, models.ReminderStatusSending, now).
+		Order("send_at ASC").
+		Limit(limit).
+		Find(&reminders).Error
+	return reminders, err
+}
+
+// Claim marks the reminder as being sent by this instance for lease. It's a
+// compare-and-set on the row as read by GetDue, so when several replicas race
+// for the same reminder exactly one of them gets true.
+func (r *reminderRepository) Claim(ctx context.Context, reminder *models.Reminder, lease time.Duration) (bool, error) {
+	now := time.Now()
+	claimedUntil := now.Add(lease)
+
+	result := dbWithContext(ctx, r.db).
+		Model(&models.Reminder{}).
+		Where("id = ? AND status = ? AND attempts = ?", reminder.ID, reminder.Status, reminder.Attempts).
+		Updates(map[string]interface{}{
+			"status":        models.ReminderStatusSending,
+			"claimed_until": claimedUntil,
+			"attempts":      gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	reminder.Status = models.ReminderStatusSending
+	reminder.ClaimedUntil = &claimedUntil
+	reminder.Attempts++
+	return true, nil
+}
+
+// SaveOutcome writes the result of sending a claimed reminder. It only touches
+// the row while it's still held by that claim, so a cancellation or reschedule
+// that happened during the send isn't overwritten; it returns false then.
+func (r *reminderRepository) SaveOutcome(ctx context.Context, reminder *models.Reminder) (bool, error) {
+	result := dbWithContext(ctx, r.db).
+		Model(&models.Reminder{}).
+		Where("id = ? AND status = ? AND attempts = ?", reminder.ID, models.ReminderStatusSending, reminder.Attempts).
+		Updates(map[string]interface{}{
+			"status":        reminder.Status,
+			"claimed_until": reminder.ClaimedUntil,
+			"sent_at":       reminder.SentAt,
+			"last_error":    reminder.LastError,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
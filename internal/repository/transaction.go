@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transaction runs fn inside a database transaction. Repository calls made with
+// the context handed to fn join that transaction. When ctx already carries a
+// transaction, fn runs in a nested one (savepoint).
+func Transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	conn := db
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		conn = tx
+	}
+
+	return conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// dbWithContext returns the transaction carried by ctx, or db when there is none.
+func dbWithContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -2,8 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"event-booking-be/internal/apperrors"
 	"event-booking-be/internal/models"
-	"fmt"
 
 	"gorm.io/gorm"
 )
@@ -17,29 +18,60 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return dbWithContext(ctx, r.db).Create(user).Error
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
 	var user models.User
-	err := r.db.WithContext(ctx).First(&user, id).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("user not found")
+	err := dbWithContext(ctx, r.db).First(&user, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrUserNotFound
 	}
 	return &user, err
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
-	if err == gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("user not found")
+	err := dbWithContext(ctx, r.db).Where("email = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrUserNotFound
 	}
 	return &user, err
 }
 
 func (r *userRepository) GetAll(ctx context.Context) ([]*models.User, error) {
 	var users []*models.User
-	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&users).Error
+	err := dbWithContext(ctx, r.db).Order("created_at DESC").Find(&users).Error
 	return users, err
 }
+
+func (r *userRepository) UpdateRole(ctx context.Context, id int, role string) error {
+	result := dbWithContext(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) GetByCalendarTokenHash(ctx context.Context, hash string) (*models.User, error) {
+	var user models.User
+	err := dbWithContext(ctx, r.db).Where("calendar_token_hash = ?", hash).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrUserNotFound
+	}
+	return &user, err
+}
+
+func (r *userRepository) UpdateCalendarTokenHash(ctx context.Context, id int, hash string) error {
+	result := dbWithContext(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Update("calendar_token_hash", hash)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return dbWithContext(ctx, r.db).Create(subscription).Error
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, id int) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := dbWithContext(ctx, r.db).First(&subscription, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrWebhookNotFound
+	}
+	return &subscription, err
+}
+
+func (r *webhookRepository) GetSubscriptionsByOrganizer(ctx context.Context, organizerID int) ([]*models.WebhookSubscription, error) {
+	var subscriptions []*models.WebhookSubscription
+	err := dbWithContext(ctx, r.db).
+		Where("organizer_id = ?", organizerID).
+		Order("created_at DESC").
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) GetActiveSubscriptionsByOrganizer(ctx context.Context, organizerID int) ([]*models.WebhookSubscription, error) {
+	var subscriptions []*models.WebhookSubscription
+	err := dbWithContext(ctx, r.db).
+		Where("organizer_id = ? AND active = ?", organizerID, true).
+		Order("id ASC").
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	result := dbWithContext(ctx, r.db).
+		Model(&models.WebhookSubscription{}).
+		Where("id = ?", subscription.ID).
+		Select("url", "event_types", "active", "consecutive_failures", "disabled_at").
+		Updates(subscription)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	result := dbWithContext(ctx, r.db).Delete(&models.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) RecordSubscriptionSuccess(ctx context.Context, id int) error {
+	return dbWithContext(ctx, r.db).
+		Model(&models.WebhookSubscription{}).
+		Where("id = ?", id).
+		UpdateColumn("consecutive_failures", 0).Error
+}
+
+// RecordSubscriptionFailure bumps the failure counter and disables the
+// subscription once it reaches disableAfter consecutive failures.
+func (r *webhookRepository) RecordSubscriptionFailure(ctx context.Context, id int, disableAfter int) error {
+	db := dbWithContext(ctx, r.db)
+
+	err := db.Model(&models.WebhookSubscription{}).
+		Where("id = ?", id).
+		UpdateColumn("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Model(&models.WebhookSubscription{}).
+		Where("id = ? AND active = ? AND consecutive_failures >= ?", id, true, disableAfter).
+		Updates(map[string]interface{}{
+			"active":      false,
+			"disabled_at": time.Now(),
+		}).Error
+}
+
+// CreateDelivery ignores duplicates of the same outbox message for the same
+// subscription, so a re-relayed message doesn't trigger a second delivery.
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = time.Now()
+	}
+	return dbWithContext(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(delivery).Error
+}
+
+// GetDueDeliveries returns pending deliveries whose next attempt is due. Rows
+// are locked with SKIP LOCKED so replicas don't send the same delivery twice.
+func (r *webhookRepository) GetDueDeliveries(ctx context.Context, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	err := dbWithContext(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, time.Now()).
+		Order("id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDeliveries holds deliveries returned by GetDueDeliveries until the
+// given time, so they aren't picked up again while they're sent outside the
+// fetching transaction. A worker that dies mid-batch leaves them to be retried
+// once the claim runs out.
+func (r *webhookRepository) ClaimDeliveries(ctx context.Context, ids []int64, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return dbWithContext(ctx, r.db).
+		Model(&models.WebhookDelivery{}).
+		Where("id IN ?", ids).
+		Update("next_attempt_at", until).Error
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return dbWithContext(ctx, r.db).Save(delivery).Error
+}
+
+func (r *webhookRepository) GetDeliveriesBySubscription(ctx context.Context, subscriptionID int, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	err := dbWithContext(ctx, r.db).
+		Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
@@ -3,25 +3,66 @@ package routes
 import (
 	"event-booking-be/internal/handler"
 	"event-booking-be/internal/middleware"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/ratelimit"
+	"event-booking-be/internal/service"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// RateLimits caps requests per client for each route group. A zero Limit
+// leaves the group unlimited.
+type RateLimits struct {
+	Auth        ratelimit.Limit
+	Events      ratelimit.Limit
+	EventWrites ratelimit.Limit
+	Bookings    ratelimit.Limit
+	Webhooks    ratelimit.Limit
+	Users       ratelimit.Limit
+	Admin       ratelimit.Limit
+	Organizer   ratelimit.Limit
+	Calendar    ratelimit.Limit
+}
+
 type Router struct {
-	userHandler    *handler.UserHandler
-	eventHandler   *handler.EventHandler
-	bookingHandler *handler.BookingHandler
+	userHandler         *handler.UserHandler
+	eventHandler        *handler.EventHandler
+	bookingHandler      *handler.BookingHandler
+	webhookHandler      *handler.WebhookHandler
+	availabilityHandler *handler.AvailabilityHandler
+	auditHandler        *handler.AuditHandler
+	analyticsHandler    *handler.AnalyticsHandler
+	calendarHandler     *handler.CalendarHandler
+	userService         service.UserService
+	limiter             ratelimit.Store
+	limits              RateLimits
 }
 
 func NewRouter(
 	userHandler *handler.UserHandler,
 	eventHandler *handler.EventHandler,
 	bookingHandler *handler.BookingHandler,
+	webhookHandler *handler.WebhookHandler,
+	availabilityHandler *handler.AvailabilityHandler,
+	auditHandler *handler.AuditHandler,
+	analyticsHandler *handler.AnalyticsHandler,
+	calendarHandler *handler.CalendarHandler,
+	userService service.UserService,
+	limiter ratelimit.Store,
+	limits RateLimits,
 ) *Router {
 	return &Router{
-		userHandler:    userHandler,
-		eventHandler:   eventHandler,
-		bookingHandler: bookingHandler,
+		userHandler:         userHandler,
+		eventHandler:        eventHandler,
+		bookingHandler:      bookingHandler,
+		webhookHandler:      webhookHandler,
+		availabilityHandler: availabilityHandler,
+		auditHandler:        auditHandler,
+		analyticsHandler:    analyticsHandler,
+		calendarHandler:     calendarHandler,
+		userService:         userService,
+		limiter:             limiter,
+		limits:              limits,
 	}
 }
 
@@ -29,28 +70,73 @@ func (r *Router) Setup(app *fiber.App) {
 	api := app.Group("/api/v1")
 
 	// Public routes
-	auth := api.Group("/auth")
+	auth := api.Group("/auth", r.rateLimit("auth", r.limits.Auth))
 	auth.Post("/register", r.userHandler.Register)
 	auth.Post("/login", r.userHandler.Login)
 
-	// Event routes (public read, protected write)
+	// Event routes (public read, protected write). Reads and writes share a
+	// prefix but not a limit, so each route carries its own: per IP for
+	// reads, per user, after auth, for writes.
 	events := api.Group("/events")
-	events.Get("/", r.eventHandler.GetAllEvents)
-	events.Get("/:id", r.eventHandler.GetEvent)
-	events.Get("/:id/statistics", r.eventHandler.GetEventStatistics)
-	events.Post("/", r.eventHandler.CreateEvent)
-	events.Put("/:id", r.eventHandler.UpdateEvent)
-	events.Delete("/:id", r.eventHandler.DeleteEvent)
+	read := r.rateLimit("events", r.limits.Events)
+	events.Get("/", read, r.eventHandler.GetAllEvents)
+	events.Get("/calendar.ics", read, r.calendarHandler.GetUpcomingCalendar)
+	events.Get("/:id", read, r.eventHandler.GetEvent)
+	events.Get("/:id/calendar.ics", read, r.calendarHandler.GetEventCalendar)
+	events.Get("/:id/statistics", read, r.eventHandler.GetEventStatistics)
+	events.Get("/:id/availability/stream", read, r.availabilityHandler.StreamAvailability)
+	authed, write := middleware.AuthMiddleware(), r.rateLimit("event-writes", r.limits.EventWrites)
+	events.Post("/", authed, write, r.eventHandler.CreateEvent)
+	events.Post("/import", authed, write, r.eventHandler.ImportEvents)
+	events.Put("/:id", authed, write, r.eventHandler.UpdateEvent)
+	events.Delete("/:id", authed, write, r.eventHandler.DeleteEvent)
 
 	// Protected booking routes
-	bookings := api.Group("/bookings", middleware.AuthMiddleware())
+	bookings := api.Group("/bookings", middleware.AuthMiddleware(), r.rateLimit("bookings", r.limits.Bookings))
 	bookings.Post("/", r.bookingHandler.CreateBooking)
 	bookings.Get("/", r.bookingHandler.GetUserBookings)
 	bookings.Get("/:id", r.bookingHandler.GetBooking)
+	bookings.Get("/:id/history", r.bookingHandler.GetBookingHistory)
 	bookings.Post("/:id/confirm", r.bookingHandler.ConfirmPayment)
 	bookings.Post("/:id/cancel", r.bookingHandler.CancelBooking)
+	bookings.Post("/:id/check-in", r.bookingHandler.CheckIn)
+
+	// Protected organizer webhook routes
+	webhooks := api.Group("/webhooks", middleware.AuthMiddleware(), r.rateLimit("webhooks", r.limits.Webhooks))
+	webhooks.Post("/", r.webhookHandler.CreateSubscription)
+	webhooks.Get("/", r.webhookHandler.GetSubscriptions)
+	webhooks.Get("/:id", r.webhookHandler.GetSubscription)
+	webhooks.Put("/:id", r.webhookHandler.UpdateSubscription)
+	webhooks.Delete("/:id", r.webhookHandler.DeleteSubscription)
+	webhooks.Get("/:id/deliveries", r.webhookHandler.GetDeliveries)
+	webhooks.Post("/:id/test", r.webhookHandler.SendTestEvent)
 
 	// Protected user routes
-	users := api.Group("/users", middleware.AuthMiddleware())
+	users := api.Group("/users", middleware.AuthMiddleware(), r.rateLimit("users", r.limits.Users))
 	users.Get("/profile", r.userHandler.GetProfile)
+	users.Post("/calendar-token", r.calendarHandler.IssueToken)
+
+	// Private calendar feeds, authenticated by the token in the URL
+	calendar := api.Group("/calendar", r.rateLimit("calendar", r.limits.Calendar))
+	calendar.Get("/:token/bookings.ics", r.calendarHandler.GetUserCalendar)
+
+	// Protected organizer dashboard routes
+	organizer := api.Group("/organizer", middleware.AuthMiddleware(), r.rateLimit("organizer", r.limits.Organizer))
+	organizer.Get("/events/analytics", r.analyticsHandler.CompareEvents)
+	organizer.Get("/events/:id/analytics", r.analyticsHandler.GetEventAnalytics)
+	organizer.Get("/events/:id/attendees", r.bookingHandler.ExportAttendees)
+
+	// Admin routes
+	admin := api.Group("/admin", middleware.AuthMiddleware(), r.rateLimit("admin", r.limits.Admin),
+		middleware.RequireRole(r.userService, models.UserRoleAdmin))
+	admin.Get("/audit-logs", r.auditHandler.ListAuditLogs)
+}
+
+// rateLimit limits a route group. Groups behind AuthMiddleware are limited
+// per user, public ones per IP.
+func (r *Router) rateLimit(group string, limit ratelimit.Limit) fiber.Handler {
+	if r.limiter == nil {
+		limit = ratelimit.Limit{}
+	}
+	return middleware.RateLimit(r.limiter, group, limit)
 }
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"fmt"
+)
+
+// isOrganizerOrAdmin reports whether userID organizes event or is an admin,
+// the two kinds of user allowed to see an event's bookings.
+func isOrganizerOrAdmin(ctx context.Context, userRepo repository.UserRepository, userID int, event *models.Event) (bool, error) {
+	if event != nil && event.OrganizerID != nil && *event.OrganizerID == userID {
+		return true, nil
+	}
+
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil && !apperrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user != nil && user.Role == models.UserRoleAdmin, nil
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	defaultAnalyticsDays = 30
+	// maxAnalyticsBuckets bounds a series: a month of hours, or a year of days.
+	maxAnalyticsBuckets = 24 * 31
+	maxAnalyticsDays    = 366
+)
+
+type analyticsService struct {
+	eventRepo   repository.EventRepository
+	bookingRepo repository.BookingRepository
+	userRepo    repository.UserRepository
+}
+
+// NewAnalyticsService aggregates bookings for organizer dashboards. The
+// repository returns one row per booking active in the range and the
+// bucketing happens here, so timezones work the same on every database.
+func NewAnalyticsService(
+	eventRepo repository.EventRepository,
+	bookingRepo repository.BookingRepository,
+	userRepo repository.UserRepository,
+) AnalyticsService {
+	return &analyticsService{
+		eventRepo:   eventRepo,
+		bookingRepo: bookingRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// GetEventAnalytics returns the time series of one event. Only its organizer
+// and admins can see it; anyone else gets ErrEventNotFound.
+func (s *analyticsService) GetEventAnalytics(ctx context.Context, userID, eventID int, rng models.AnalyticsRange) (*models.EventAnalytics, error) {
+	rng, loc, err := normalizeRange(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if err := s.checkOrganizer(ctx, userID, event); err != nil {
+		return nil, err
+	}
+
+	activity, err := s.bookingRepo.GetActivity(ctx, []int{eventID}, rng.From, rng.To)
+	if err != nil {
+		return nil, fmt.Errorf("can't get booking activity: %w", err)
+	}
+
+	series := newSeries(rng, loc)
+	for _, booking := range activity {
+		series.add(booking)
+	}
+
+	return &models.EventAnalytics{
+		EventID:      event.ID,
+		EventName:    event.Name,
+		Interval:     rng.Interval,
+		Timezone:     rng.Timezone,
+		From:         rng.From,
+		To:           rng.To,
+		Totals:       series.totals(),
+		ByTicketType: series.byTicketType(),
+		Series:       series.buckets,
+	}, nil
+}
+
+// CompareEvents returns the totals of every event userID organizes, in date
+// order.
+func (s *analyticsService) CompareEvents(ctx context.Context, userID int, rng models.AnalyticsRange) ([]*models.EventAnalyticsSummary, error) {
+	rng, loc, err := normalizeRange(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.eventRepo.GetByOrganizer(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get organizer events: %w", err)
+	}
+	summaries := make([]*models.EventAnalyticsSummary, 0, len(events))
+	if len(events) == 0 {
+		return summaries, nil
+	}
+
+	ids := make([]int, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	activity, err := s.bookingRepo.GetActivity(ctx, ids, rng.From, rng.To)
+	if err != nil {
+		return nil, fmt.Errorf("can't get booking activity: %w", err)
+	}
+
+	byEvent := make(map[int]*series, len(events))
+	for _, event := range events {
+		byEvent[event.ID] = newSeries(rng, loc)
+	}
+	for _, booking := range activity {
+		byEvent[booking.EventID].add(booking)
+	}
+
+	for _, event := range events {
+		totals := byEvent[event.ID].totals()
+		summary := &models.EventAnalyticsSummary{
+			EventID:     event.ID,
+			EventName:   event.Name,
+			DateTime:    event.DateTime,
+			Capacity:    event.Capacity,
+			TicketPrice: event.TicketPrice,
+			Totals:      totals,
+		}
+		if event.Capacity > 0 {
+			summary.SellThrough = float64(totals.TicketsSold) / float64(event.Capacity)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func (s *analyticsService) checkOrganizer(ctx context.Context, userID int, event *models.Event) error {
+	allowed, err := isOrganizerOrAdmin(ctx, s.userRepo, userID, event)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return apperrors.ErrEventNotFound
+	}
+	return nil
+}
+
+// normalizeRange fills in the defaults (daily buckets in UTC over the last
+// 30 days) and rejects ranges that are empty or would have too many buckets.
+func normalizeRange(rng models.AnalyticsRange) (models.AnalyticsRange, *time.Location, error) {
+	if rng.Interval == "" {
+		rng.Interval = models.AnalyticsDay
+	}
+	if rng.Timezone == "" {
+		rng.Timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(rng.Timezone)
+	if err != nil {
+		return rng, nil, apperrors.ErrInvalidTimezone.Withf("unknown timezone %q", rng.Timezone)
+	}
+
+	if rng.To.IsZero() {
+		rng.To = time.Now()
+	}
+	if rng.From.IsZero() {
+		rng.From = rng.To.AddDate(0, 0, -defaultAnalyticsDays)
+	}
+	if !rng.From.Before(rng.To) {
+		return rng, nil, apperrors.ErrInvalidRange.Withf("from must be before to")
+	}
+
+	switch rng.Interval {
+	case models.AnalyticsHour:
+		if rng.To.Sub(rng.From) > maxAnalyticsBuckets*time.Hour {
+			return rng, nil, apperrors.ErrInvalidRange.Withf("hourly ranges are limited to %d days", maxAnalyticsBuckets/24)
+		}
+	case models.AnalyticsDay:
+		if rng.To.Sub(rng.From) > maxAnalyticsDays*24*time.Hour {
+			return rng, nil, apperrors.ErrInvalidRange.Withf("daily ranges are limited to %d days", maxAnalyticsDays)
+		}
+	default:
+		return rng, nil, apperrors.ErrInvalidRange.Withf("unknown interval %q", rng.Interval)
+	}
+
+	return rng, loc, nil
+}
+
+// series accumulates bookings into zero-filled buckets covering the range.
+type series struct {
+	rng     models.AnalyticsRange
+	loc     *time.Location
+	buckets []models.AnalyticsBucket
+	index   map[int64]int // bucket start (unix seconds) -> position
+
+	// the cohort of bookings created in the range, for the rates
+	created, confirmed, cancelled, expired int
+
+	sales map[string]*models.TicketTypeSales // by ticket type
+}
+
+func newSeries(rng models.AnalyticsRange, loc *time.Location) *series {
+	s := &series{rng: rng, loc: loc, index: map[int64]int{}, sales: map[string]*models.TicketTypeSales{}}
+	for start := s.truncate(rng.From); start.Before(rng.To); start = s.next(start) {
+		s.index[start.Unix()] = len(s.buckets)
+		s.buckets = append(s.buckets, models.AnalyticsBucket{Start: start})
+	}
+	return s
+}
+
+// truncate returns the start of the bucket t falls in, in the series' zone.
+func (s *series) truncate(t time.Time) time.Time {
+	t = t.In(s.loc)
+	if s.rng.Interval == models.AnalyticsHour {
+		// truncate by the offset in effect rather than through time.Date, so
+		// the hour repeated when clocks go back gets a bucket of its own
+		_, offset := t.Zone()
+		shift := time.Duration(offset) * time.Second
+		return t.Add(shift).Truncate(time.Hour).Add(-shift).In(s.loc)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.loc)
+}
+
+// next returns the start of the bucket after start. Days follow the
+// calendar, so they are 23 or 25 hours long across DST changes.
+func (s *series) next(start time.Time) time.Time {
+	if s.rng.Interval == models.AnalyticsHour {
+		return s.truncate(start.Add(time.Hour))
+	}
+	return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, s.loc)
+}
+
+// bucket returns the bucket t falls in, or nil if t is outside the range.
+func (s *series) bucket(t *time.Time) *models.AnalyticsBucket {
+	if t == nil || t.Before(s.rng.From) || !t.Before(s.rng.To) {
+		return nil
+	}
+	i, ok := s.index[s.truncate(*t).Unix()]
+	if !ok {
+		return nil
+	}
+	return &s.buckets[i]
+}
+
+func (s *series) add(booking *models.BookingActivity) {
+	if b := s.bucket(&booking.CreatedAt); b != nil {
+		b.BookingsCreated++
+		s.created++
+		switch booking.Status {
+		case models.BookingStatusConfirmed:
+			s.confirmed++
+		case models.BookingStatusCancelled:
+			s.cancelled++
+		case models.BookingStatusExpired:
+			s.expired++
+		}
+	}
+	if b := s.bucket(booking.ConfirmedAt); b != nil {
+		b.BookingsConfirmed++
+		b.TicketsSold += booking.TicketCount
+		b.Revenue += booking.TotalPrice
+
+		sales, ok := s.sales[booking.TicketType]
+		if !ok {
+			sales = &models.TicketTypeSales{TicketType: booking.TicketType}
+			s.sales[booking.TicketType] = sales
+		}
+		sales.TicketsSold += booking.TicketCount
+		sales.Revenue += booking.TotalPrice
+	}
+	if b := s.bucket(booking.CancelledAt); b != nil {
+		b.BookingsCancelled++
+	}
+	if b := s.bucket(booking.ExpiredAt); b != nil {
+		b.BookingsExpired++
+	}
+}
+
+func (s *series) totals() models.AnalyticsTotals {
+	var totals models.AnalyticsTotals
+	for _, b := range s.buckets {
+		totals.BookingsCreated += b.BookingsCreated
+		totals.BookingsConfirmed += b.BookingsConfirmed
+		totals.BookingsCancelled += b.BookingsCancelled
+		totals.BookingsExpired += b.BookingsExpired
+		totals.TicketsSold += b.TicketsSold
+		totals.Revenue += b.Revenue
+	}
+	if s.created > 0 {
+		totals.ConversionRate = float64(s.confirmed) / float64(s.created)
+		totals.ExpiryRate = float64(s.expired) / float64(s.created)
+		totals.CancellationRate = float64(s.cancelled) / float64(s.created)
+	}
+	return totals
+}
+
+// byTicketType returns the sales of each ticket type sold in the range, the
+// default ticket first and the rest by name. They add up to the totals.
+func (s *series) byTicketType() []models.TicketTypeSales {
+	sales := make([]models.TicketTypeSales, 0, len(s.sales))
+	for _, ticketType := range s.sales {
+		sales = append(sales, *ticketType)
+	}
+	sort.Slice(sales, func(i, j int) bool {
+		a, b := sales[i].TicketType, sales[j].TicketType
+		if (a == "") != (b == "") {
+			return a == ""
+		}
+		return a < b
+	})
+	return sales
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/audit"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"fmt"
+)
+
+// recordAudit appends an audit entry for a mutation, attributed to the actor
+// and request carried by ctx. Like recordOutboxEvent it must be called with
+// the transaction context of the change, so the entry commits or rolls back
+// with it. before is nil for creations, after is nil for deletions.
+func recordAudit(
+	ctx context.Context,
+	auditRepo repository.AuditRepository,
+	action string,
+	entityType string,
+	entityID int,
+	before, after interface{},
+) error {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", action, err)
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s changes: %w", action, err)
+	}
+
+	actor := audit.ActorFrom(ctx)
+	entry := &models.AuditLog{
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		ActorName:  actor.Name,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    string(data),
+		RequestID:  audit.RequestIDFrom(ctx),
+	}
+	if err := auditRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record %s audit entry: %w", action, err)
+	}
+	return nil
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+func (s *auditService) ListAuditLogs(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	entries, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("can't list audit logs: %w", err)
+	}
+	return entries, nil
+}
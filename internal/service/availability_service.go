@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/realtime"
+	"event-booking-be/internal/repository"
+	"fmt"
+	"time"
+)
+
+type availabilityService struct {
+	eventRepo repository.EventRepository
+	hub       *realtime.Hub
+}
+
+func NewAvailabilityService(eventRepo repository.EventRepository, hub *realtime.Hub) AvailabilityService {
+	return &availabilityService{
+		eventRepo: eventRepo,
+		hub:       hub,
+	}
+}
+
+func (s *availabilityService) GetAvailability(ctx context.Context, eventID int) (*realtime.Availability, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &realtime.Availability{
+		EventID:     event.ID,
+		TicketsLeft: event.TotalTickets,
+		UpdatedAt:   event.UpdatedAt,
+	}, nil
+}
+
+func (s *availabilityService) Subscribe(eventID int) (*realtime.Subscription, error) {
+	return s.hub.Subscribe(eventID)
+}
+
+// Dispatch publishes the event's current availability whenever a booking
+// takes or releases tickets, or the event's inventory is edited. It's
+// registered as a best-effort outbox sink: a missed update is superseded by
+// the next one, so it isn't worth retrying the message for.
+func (s *availabilityService) Dispatch(ctx context.Context, message *models.OutboxMessage) error {
+	var eventID int
+
+	switch message.EventType {
+	case models.DomainEventBookingCreated, models.DomainEventBookingCancelled, models.DomainEventBookingExpired:
+		var payload models.BookingEventPayload
+		if err := json.Unmarshal([]byte(message.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid booking payload: %w", err)
+		}
+		eventID = payload.EventID
+	case models.DomainEventEventUpdated:
+		eventID = message.AggregateID
+	default:
+		return nil
+	}
+
+	availability, err := s.GetAvailability(ctx, eventID)
+	if err != nil {
+		return skipMissing(err)
+	}
+	availability.UpdatedAt = time.Now()
+
+	return s.hub.Publish(ctx, *availability)
+}
@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/logging"
+	"event-booking-be/internal/metrics"
 	"event-booking-be/internal/models"
 	"event-booking-be/internal/repository"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,36 +18,67 @@ import (
 type bookingService struct {
 	bookingRepo repository.BookingRepository
 	eventRepo   repository.EventRepository
+	userRepo    repository.UserRepository
+	outboxRepo  repository.OutboxRepository
+	jobRepo     repository.JobRepository
+	auditRepo   repository.AuditRepository
 	db          *gorm.DB
 	timeout     time.Duration
+	logger      *slog.Logger
 }
 
 func NewBookingService(
 	bookingRepo repository.BookingRepository,
 	eventRepo repository.EventRepository,
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	jobRepo repository.JobRepository,
+	auditRepo repository.AuditRepository,
 	db *gorm.DB,
 	timeoutMinutes int,
+	logger *slog.Logger,
 ) BookingService {
 	return &bookingService{
 		bookingRepo: bookingRepo,
 		eventRepo:   eventRepo,
+		userRepo:    userRepo,
+		outboxRepo:  outboxRepo,
+		jobRepo:     jobRepo,
+		auditRepo:   auditRepo,
 		db:          db,
 		timeout:     time.Duration(timeoutMinutes) * time.Minute,
+		logger:      logger,
 	}
 }
 
 func (s *bookingService) CreateBooking(ctx context.Context, userID int, req *models.CreateBookingRequest) (*models.Booking, error) {
 	var booking *models.Booking
-	
-	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
 		event, err := s.eventRepo.LockForUpdate(ctx, req.EventID)
 		if err != nil {
-			return fmt.Errorf("event not found")
+			return fmt.Errorf("failed to lock event: %w", err)
+		}
+
+		// the event row lock serialises bookings for the event, so the held
+		// counts can't change under us
+		if err := s.checkPurchaseLimits(ctx, event, userID, req); err != nil {
+			return err
 		}
 
 		// TODO: add caching for event data to reduce DB load
 		if event.TotalTickets < req.TicketCount {
-			return fmt.Errorf("not enough tickets available. Only %d tickets left", event.TotalTickets)
+			return apperrors.ErrNotEnoughTickets.Withf("not enough tickets available. Only %d tickets left", event.TotalTickets)
+		}
+
+		// every ticket type draws on the event's tickets; only the price differs
+		price := event.TicketPrice
+		if req.TicketType != "" {
+			ticketType, err := s.eventRepo.GetTicketType(ctx, event.ID, req.TicketType)
+			if err != nil {
+				return err
+			}
+			price = ticketType.Price
 		}
 
 		if err := s.eventRepo.DecrementTickets(ctx, req.EventID, req.TicketCount); err != nil {
@@ -50,25 +86,104 @@ func (s *bookingService) CreateBooking(ctx context.Context, userID int, req *mod
 		}
 
 		booking = &models.Booking{
-			UserID:      userID,
-			EventID:     req.EventID,
-			TicketCount: req.TicketCount,
-			TotalPrice:  float64(req.TicketCount) * event.TicketPrice,
-			Status:      models.BookingStatusPending,
-			ExpiresAt:   time.Now().Add(s.timeout),
+			UserID:            userID,
+			EventID:           req.EventID,
+			TicketCount:       req.TicketCount,
+			TotalPrice:        float64(req.TicketCount) * price,
+			Status:            models.BookingStatusPending,
+			PaymentInstrument: req.PaymentInstrument,
+			TicketType:        req.TicketType,
+			ExpiresAt:         time.Now().Add(s.timeout),
 		}
 
 		if err := s.bookingRepo.Create(ctx, booking); err != nil {
 			return fmt.Errorf("failed to create booking: %w", err)
 		}
 
-		return nil
+		err = scheduleJob(ctx, s.jobRepo, models.JobTypeExpireBooking,
+			models.ExpireBookingPayload{BookingID: booking.ID},
+			booking.ExpiresAt, fmt.Sprintf("%s:%d", models.JobTypeExpireBooking, booking.ID))
+		if err != nil {
+			return err
+		}
+
+		if err := recordAudit(ctx, s.auditRepo, models.AuditBookingCreated, models.AuditEntityBooking, booking.ID, nil, booking); err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, models.DomainEventBookingCreated, booking)
 	})
 
-	return booking, err
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.RecordBooking("created", booking.EventID, booking.TicketCount)
+	s.logger.InfoContext(bookingContext(ctx, booking), "Booking created",
+		"ticket_count", booking.TicketCount, "expires_at", booking.ExpiresAt)
+	return booking, nil
 }
 
-func (s *bookingService) GetBooking(ctx context.Context, id int) (*models.BookingWithDetails, error) {
+// checkPurchaseLimits enforces the event's anti-scalping rules on a new
+// order. Must run in the transaction holding the event lock.
+func (s *bookingService) checkPurchaseLimits(ctx context.Context, event *models.Event, userID int, req *models.CreateBookingRequest) error {
+	if event.MaxTicketsPerOrder > 0 && req.TicketCount > event.MaxTicketsPerOrder {
+		return apperrors.ErrOrderLimitExceeded.Withf("at most %d tickets per order for this event", event.MaxTicketsPerOrder)
+	}
+
+	if event.MaxTicketsPerUser > 0 {
+		held, err := s.bookingRepo.SumHeldTicketsByUser(ctx, event.ID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to count user tickets: %w", err)
+		}
+		if held+req.TicketCount > event.MaxTicketsPerUser {
+			return apperrors.ErrUserLimitExceeded.Withf("at most %d tickets per person for this event; you already hold %d",
+				event.MaxTicketsPerUser, held)
+		}
+	}
+
+	if event.MaxTicketsPerPaymentInstrument > 0 {
+		if req.PaymentInstrument == "" {
+			return apperrors.ErrInstrumentRequired
+		}
+		held, err := s.bookingRepo.SumHeldTicketsByPaymentInstrument(ctx, event.ID, req.PaymentInstrument)
+		if err != nil {
+			return fmt.Errorf("failed to count payment instrument tickets: %w", err)
+		}
+		if held+req.TicketCount > event.MaxTicketsPerPaymentInstrument {
+			return apperrors.ErrInstrumentLimitExceeded.Withf("at most %d tickets per payment method for this event; %d already booked with it",
+				event.MaxTicketsPerPaymentInstrument, held)
+		}
+	}
+
+	if event.MaxTicketsPerEmailDomain > 0 {
+		held, err := s.bookingRepo.SumHeldTicketsByEmailDomain(ctx, event.ID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to count email domain tickets: %w", err)
+		}
+		if held+req.TicketCount > event.MaxTicketsPerEmailDomain {
+			return apperrors.ErrDomainLimitExceeded.Withf("at most %d tickets per email domain for this event; %d already booked",
+				event.MaxTicketsPerEmailDomain, held)
+		}
+	}
+
+	return nil
+}
+
+func (s *bookingService) GetBooking(ctx context.Context, userID, id int) (*models.BookingWithDetails, error) {
+	booking, err := s.LookupBooking(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAccess(ctx, userID, &booking.Booking); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// LookupBooking returns any booking, without checking who is asking. It is
+// for operator tools such as the admin CLI.
+func (s *bookingService) LookupBooking(ctx context.Context, id int) (*models.BookingWithDetails, error) {
 	booking, err := s.bookingRepo.GetWithDetails(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get booking: %w", err)
@@ -76,6 +191,54 @@ func (s *bookingService) GetBooking(ctx context.Context, id int) (*models.Bookin
 	return booking, nil
 }
 
+// getAccessibleBooking returns the booking if userID may act on it.
+func (s *bookingService) getAccessibleBooking(ctx context.Context, userID, id int) (*models.Booking, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if err := s.checkAccess(ctx, userID, booking); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// checkAccess lets the booking's owner, the organizer of its event and
+// admins through. Anyone else gets ErrBookingNotFound rather than a 403, so
+// booking IDs can't be probed.
+func (s *bookingService) checkAccess(ctx context.Context, userID int, booking *models.Booking) error {
+	if booking.UserID == userID {
+		return nil
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, booking.EventID)
+	if err != nil && !apperrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+	allowed, err := isOrganizerOrAdmin(ctx, s.userRepo, userID, event)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return apperrors.ErrBookingNotFound
+	}
+	return nil
+}
+
+// GetBookingHistory returns every status the booking went through, oldest
+// first, starting with its creation.
+func (s *bookingService) GetBookingHistory(ctx context.Context, userID, id int) ([]*models.BookingStatusChange, error) {
+	if _, err := s.getAccessibleBooking(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	history, err := s.bookingRepo.GetStatusHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't get booking history: %w", err)
+	}
+	return history, nil
+}
+
 func (s *bookingService) GetUserBookings(ctx context.Context, userID int) ([]*models.Booking, error) {
 	bookings, err := s.bookingRepo.GetByUserID(ctx, userID)
 	if err != nil {
@@ -84,44 +247,90 @@ func (s *bookingService) GetUserBookings(ctx context.Context, userID int) ([]*mo
 	return bookings, nil
 }
 
-func (s *bookingService) ConfirmPayment(ctx context.Context, bookingID int) error {
-	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+func (s *bookingService) ConfirmPayment(ctx context.Context, userID, bookingID int) error {
+	booking, err := s.getAccessibleBooking(ctx, userID, bookingID)
 	if err != nil {
-		return fmt.Errorf("booking not found: %w", err)
+		return err
 	}
 
 	if booking.Status != models.BookingStatusPending {
-		return fmt.Errorf("booking is not in pending status")
+		return apperrors.BookingState(string(booking.Status))
 	}
 
 	if time.Now().After(booking.ExpiresAt) {
-		return fmt.Errorf("booking has expired")
+		return apperrors.ErrBookingExpired
 	}
 
-	if err := s.bookingRepo.UpdateStatus(ctx, bookingID, models.BookingStatusConfirmed); err != nil {
-		return fmt.Errorf("failed to confirm booking: %w", err)
+	// the checks above are only for friendly errors: an expiry or a
+	// cancellation may land in between, and only one of them can win
+	return s.confirmBooking(ctx, booking, models.AuditBookingConfirmed)
+}
+
+// statusReasons is the reason the status history gives for each action.
+var statusReasons = map[string]string{
+	models.AuditBookingConfirmed:      models.BookingReasonPaymentConfirmed,
+	models.AuditBookingCancelled:      models.BookingReasonUserCancelled,
+	models.AuditBookingExpired:        models.BookingReasonExpiredByWorker,
+	models.AuditBookingForceConfirmed: models.BookingReasonForceConfirmed,
+	models.AuditBookingForceExpired:   models.BookingReasonForceExpired,
+}
+
+// confirmBooking moves a pending booking to CONFIRMED; action is what the
+// audit log calls it.
+func (s *bookingService) confirmBooking(ctx context.Context, booking *models.Booking, action string) error {
+	before := *booking
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		err := s.bookingRepo.TransitionStatus(ctx, booking.ID, models.BookingStatusPending, models.BookingStatusConfirmed, statusReasons[action])
+		if err != nil {
+			return fmt.Errorf("failed to confirm booking: %w", err)
+		}
+
+		booking.Status = models.BookingStatusConfirmed
+		if err := recordAudit(ctx, s.auditRepo, action, models.AuditEntityBooking, booking.ID, &before, booking); err != nil {
+			return err
+		}
+		return s.recordEvent(ctx, models.DomainEventBookingConfirmed, booking)
+	})
+	if err != nil {
+		return err
 	}
 
+	metrics.RecordBooking("confirmed", booking.EventID, booking.TicketCount)
+	s.logger.InfoContext(bookingContext(ctx, booking), "Booking confirmed")
 	return nil
 }
 
-func (s *bookingService) CancelBooking(ctx context.Context, bookingID int) error {
-	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+func (s *bookingService) CancelBooking(ctx context.Context, userID, bookingID int) error {
+	booking, err := s.getAccessibleBooking(ctx, userID, bookingID)
 	if err != nil {
-		return fmt.Errorf("booking not found: %w", err)
+		return err
 	}
 
-	if booking.Status == models.BookingStatusCancelled {
-		return fmt.Errorf("booking already cancelled")
+	switch booking.Status {
+	case models.BookingStatusPending:
+	case models.BookingStatusConfirmed:
+		return apperrors.ErrBookingConfirmed.Withf("cannot cancel confirmed booking")
+	default:
+		return apperrors.BookingState(string(booking.Status))
 	}
 
-	if booking.Status == models.BookingStatusConfirmed {
-		return fmt.Errorf("cannot cancel confirmed booking")
+	return s.releaseBooking(ctx, booking, models.BookingStatusCancelled, models.AuditBookingCancelled)
+}
+
+// releaseBooking moves a pending booking to status (CANCELLED or EXPIRED) and
+// gives its tickets back. The tickets are only released if the transition
+// wins, so a booking confirmed concurrently keeps them. action is what the
+// audit log calls it.
+func (s *bookingService) releaseBooking(ctx context.Context, booking *models.Booking, status models.BookingStatus, action string) error {
+	eventType := models.DomainEventBookingCancelled
+	if status == models.BookingStatusExpired {
+		eventType = models.DomainEventBookingExpired
 	}
 
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := s.bookingRepo.UpdateStatus(ctx, bookingID, models.BookingStatusCancelled); err != nil {
-			return fmt.Errorf("failed to cancel booking: %w", err)
+	before := *booking
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.bookingRepo.TransitionStatus(ctx, booking.ID, models.BookingStatusPending, status, statusReasons[action]); err != nil {
+			return fmt.Errorf("failed to release booking: %w", err)
 		}
 
 		// restore tickets
@@ -129,22 +338,253 @@ func (s *bookingService) CancelBooking(ctx context.Context, bookingID int) error
 			return fmt.Errorf("failed to release tickets: %w", err)
 		}
 
-		return nil
+		booking.Status = status
+		if err := recordAudit(ctx, s.auditRepo, action, models.AuditEntityBooking, booking.ID, &before, booking); err != nil {
+			return err
+		}
+		return s.recordEvent(ctx, eventType, booking)
 	})
+	if err != nil {
+		return err
+	}
+
+	metrics.RecordBooking(strings.ToLower(string(status)), booking.EventID, booking.TicketCount)
+	s.logger.InfoContext(bookingContext(ctx, booking), "Booking released",
+		"status", status, "ticket_count", booking.TicketCount)
+	return nil
+}
+
+// ExpireBooking releases a pending booking whose payment window has passed.
+// It runs from the expiry job scheduled by CreateBooking and is a no-op for
+// bookings that were confirmed or cancelled in the meantime.
+func (s *bookingService) ExpireBooking(ctx context.Context, bookingID int) error {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return skipMissing(err)
+	}
+
+	if booking.Status != models.BookingStatusPending {
+		return nil
+	}
+
+	if time.Now().Before(booking.ExpiresAt) {
+		return apperrors.ErrBookingNotExpired.Withf("booking %d doesn't expire until %s", bookingID, booking.ExpiresAt.Format(time.RFC3339))
+	}
+
+	err = s.releaseBooking(ctx, booking, models.BookingStatusExpired, models.AuditBookingExpired)
+	if apperrors.KindOf(err) == apperrors.KindConflict {
+		// lost the race to a confirmation or cancellation; nothing left to do
+		return nil
+	}
+	return err
 }
 
+// ProcessExpiredBookings sweeps every pending booking past its expiry. Expiry
+// jobs handle this normally; the sweep catches bookings created before they
+// existed.
 func (s *bookingService) ProcessExpiredBookings(ctx context.Context) error {
+	start := time.Now()
+
 	expiredBookings, err := s.bookingRepo.GetExpiredPending(ctx)
 	if err != nil {
 		return fmt.Errorf("can't fetch expired bookings: %w", err)
 	}
+	defer func() { metrics.ObserveExpirySweep(len(expiredBookings), time.Since(start)) }()
 
 	for _, booking := range expiredBookings {
-		if err := s.CancelBooking(ctx, booking.ID); err != nil {
+		if err := s.ExpireBooking(ctx, booking.ID); err != nil {
 			// log and continue - don't want one failure to stop the whole job
-			fmt.Printf("Failed to cancel expired booking %d: %v\n", booking.ID, err)
+			s.logger.ErrorContext(bookingContext(ctx, booking), "Failed to expire booking", "error", err)
 		}
 	}
 
 	return nil
 }
+
+func (s *bookingService) ListBookings(ctx context.Context, filter models.BookingFilter) ([]*models.Booking, error) {
+	bookings, err := s.bookingRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("can't list bookings: %w", err)
+	}
+	return bookings, nil
+}
+
+// ForceExpireBooking expires a pending booking now, regardless of its
+// payment window. Operators use it to free tickets held by stuck bookings.
+func (s *bookingService) ForceExpireBooking(ctx context.Context, bookingID int) error {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to get booking: %w", err)
+	}
+
+	if booking.Status != models.BookingStatusPending {
+		return apperrors.BookingState(string(booking.Status))
+	}
+
+	return s.releaseBooking(ctx, booking, models.BookingStatusExpired, models.AuditBookingForceExpired)
+}
+
+// ForceConfirmBooking confirms a pending booking even if its payment window
+// has passed, e.g. when a payment arrived late but was captured.
+func (s *bookingService) ForceConfirmBooking(ctx context.Context, bookingID int) error {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to get booking: %w", err)
+	}
+
+	if booking.Status != models.BookingStatusPending {
+		return apperrors.BookingState(string(booking.Status))
+	}
+
+	return s.confirmBooking(ctx, booking, models.AuditBookingForceConfirmed)
+}
+
+func (s *bookingService) GetEventAttendees(ctx context.Context, eventID int, statuses []models.BookingStatus) ([]*models.Attendee, error) {
+	if _, err := s.eventRepo.GetByID(ctx, eventID); err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	if len(statuses) == 0 {
+		statuses = []models.BookingStatus{models.BookingStatusConfirmed}
+	}
+
+	attendees, err := s.bookingRepo.GetAttendees(ctx, eventID, statuses)
+	if err != nil {
+		return nil, fmt.Errorf("can't get attendees: %w", err)
+	}
+	return attendees, nil
+}
+
+// CheckIn records that a confirmed booking's holder has arrived. Only the
+// event's organizer and admins can check bookings in, and each booking only
+// once.
+func (s *bookingService) CheckIn(ctx context.Context, userID, bookingID int) (*models.Booking, error) {
+	booking, err := s.getAccessibleBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := s.eventRepo.GetByID(ctx, booking.EventID)
+	if err != nil && !apperrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	allowed, err := isOrganizerOrAdmin(ctx, s.userRepo, userID, event)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, apperrors.ErrForbidden.Withf("only the event's organizer can check bookings in")
+	}
+
+	before := *booking
+	err = repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		now := time.Now()
+		if err := s.bookingRepo.CheckIn(ctx, booking.ID, now); err != nil {
+			return fmt.Errorf("failed to check in booking: %w", err)
+		}
+
+		booking.CheckedInAt = &now
+		return recordAudit(ctx, s.auditRepo, models.AuditBookingCheckedIn, models.AuditEntityBooking, booking.ID, &before, booking)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(bookingContext(ctx, booking), "Booking checked in")
+	return booking, nil
+}
+
+// ExportAttendees checks that userID organizes the event, or is an admin,
+// and returns a stream of its confirmed attendees. The check runs up front
+// so the caller can still answer with an error before it starts writing.
+func (s *bookingService) ExportAttendees(ctx context.Context, userID, eventID int) (AttendeeStream, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	allowed, err := isOrganizerOrAdmin(ctx, s.userRepo, userID, event)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, apperrors.ErrEventNotFound
+	}
+
+	return func(ctx context.Context, fn func(*models.Attendee) error) error {
+		statuses := []models.BookingStatus{models.BookingStatusConfirmed}
+		if err := s.bookingRepo.StreamAttendees(ctx, eventID, statuses, fn); err != nil {
+			return fmt.Errorf("can't stream attendees: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// RecountInventory recomputes an event's available tickets as its capacity
+// minus the tickets held by pending and confirmed bookings, repairing any
+// drift in the running counter.
+func (s *bookingService) RecountInventory(ctx context.Context, eventID int) (*models.InventoryRecount, error) {
+	var recount *models.InventoryRecount
+
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		event, err := s.eventRepo.LockForUpdate(ctx, eventID)
+		if err != nil {
+			return fmt.Errorf("failed to lock event: %w", err)
+		}
+
+		held, err := s.bookingRepo.SumHeldTickets(ctx, eventID)
+		if err != nil {
+			return fmt.Errorf("can't count held tickets: %w", err)
+		}
+
+		recount = &models.InventoryRecount{
+			EventID:     eventID,
+			Capacity:    event.Capacity,
+			HeldTickets: held,
+			Before:      event.TotalTickets,
+			After:       event.Capacity - held,
+		}
+		if recount.After < 0 {
+			recount.Oversold = -recount.After
+			recount.After = 0
+		}
+		recount.Changed = recount.After != recount.Before
+
+		if !recount.Changed {
+			return nil
+		}
+		if err := s.eventRepo.SetAvailableTickets(ctx, eventID, recount.After); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.auditRepo, models.AuditEventInventoryRecounted, models.AuditEntityEvent, eventID,
+			map[string]int{"total_tickets": recount.Before}, map[string]int{"total_tickets": recount.After})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if recount.Changed {
+		s.logger.WarnContext(logging.With(ctx, "event_id", eventID), "Inventory recounted",
+			"before", recount.Before, "after", recount.After, "oversold", recount.Oversold)
+	}
+
+	return recount, nil
+}
+
+// bookingContext attaches the booking's IDs to ctx for logging.
+func bookingContext(ctx context.Context, booking *models.Booking) context.Context {
+	return logging.With(ctx, "booking_id", booking.ID, "event_id", booking.EventID, "user_id", booking.UserID)
+}
+
+// recordEvent writes a booking domain event to the outbox. It must be called
+// with the transaction context of the state change it describes.
+func (s *bookingService) recordEvent(ctx context.Context, eventType string, booking *models.Booking) error {
+	return recordOutboxEvent(ctx, s.outboxRepo, models.AggregateTypeBooking, booking.ID, eventType, models.BookingEventPayload{
+		BookingID:   booking.ID,
+		UserID:      booking.UserID,
+		EventID:     booking.EventID,
+		TicketCount: booking.TicketCount,
+		TotalPrice:  booking.TotalPrice,
+		Status:      booking.Status,
+		ExpiresAt:   booking.ExpiresAt,
+		OccurredAt:  time.Now(),
+	})
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalendarFeedPath is where a user's private calendar feed is served, with
+// the token in place of :token.
+const CalendarFeedPath = "/api/v1/calendar/:token/bookings.ics"
+
+type calendarService struct {
+	eventRepo   repository.EventRepository
+	bookingRepo repository.BookingRepository
+	userRepo    repository.UserRepository
+	auditRepo   repository.AuditRepository
+	db          *gorm.DB
+	uidDomain   string
+}
+
+// NewCalendarService builds iCalendar entries for events and for the events
+// users have booked. Every entry for an event has the UID
+// event-<id>@<uidDomain>, whichever feed it is in, so uidDomain must not
+// change once feeds are in use.
+func NewCalendarService(
+	eventRepo repository.EventRepository,
+	bookingRepo repository.BookingRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditRepository,
+	db *gorm.DB,
+	uidDomain string,
+) CalendarService {
+	return &calendarService{
+		eventRepo:   eventRepo,
+		bookingRepo: bookingRepo,
+		userRepo:    userRepo,
+		auditRepo:   auditRepo,
+		db:          db,
+		uidDomain:   uidDomain,
+	}
+}
+
+func (s *calendarService) GetEventEntry(ctx context.Context, eventID int) (*models.CalendarEntry, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return s.entry(event), nil
+}
+
+// GetUpcomingEntries returns every event yet to start. Events deleted before
+// they started are included as cancelled, so subscribers drop them.
+func (s *calendarService) GetUpcomingEntries(ctx context.Context) ([]*models.CalendarEntry, error) {
+	events, err := s.eventRepo.GetUpcoming(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	entries := make([]*models.CalendarEntry, len(events))
+	for i, event := range events {
+		entries[i] = s.entry(event)
+	}
+	return entries, nil
+}
+
+// GetUserEntries returns the events the owner of token holds confirmed
+// bookings for, one entry per event. Anyone with the token can read the
+// feed, as calendar apps can't send credentials; an unknown token gets
+// ErrUserNotFound.
+func (s *calendarService) GetUserEntries(ctx context.Context, token string) ([]*models.CalendarEntry, error) {
+	user, err := s.userRepo.GetByCalendarTokenHash(ctx, hashCalendarToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	bookings, err := s.bookingRepo.GetConfirmedWithEvents(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookings: %w", err)
+	}
+
+	var eventIDs []int
+	entries := make(map[int]*models.CalendarEntry)
+	lines := make(map[int][]string)
+	for _, booking := range bookings {
+		if _, seen := entries[booking.EventID]; !seen {
+			eventIDs = append(eventIDs, booking.EventID)
+			entries[booking.EventID] = s.entry(&booking.Event)
+		}
+		lines[booking.EventID] = append(lines[booking.EventID],
+			fmt.Sprintf("Booking %s: %d ticket(s)", models.BookingReference(booking.ID), booking.TicketCount))
+	}
+
+	result := make([]*models.CalendarEntry, len(eventIDs))
+	for i, eventID := range eventIDs {
+		entry := entries[eventID]
+		// the bookings go above the event's own description
+		entry.Description = strings.TrimSpace(strings.Join(lines[eventID], "\n") + "\n\n" + entry.Description)
+		result[i] = entry
+	}
+	return result, nil
+}
+
+// IssueToken gives the user a new private feed token, replacing any earlier
+// one. Only its hash is stored.
+func (s *calendarService) IssueToken(ctx context.Context, userID int) (*models.CalendarToken, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateCalendarTokenHash(ctx, userID, hashCalendarToken(token)); err != nil {
+			return fmt.Errorf("failed to store calendar token: %w", err)
+		}
+		return recordAudit(ctx, s.auditRepo, models.AuditUserCalendarTokenIssued, models.AuditEntityUser, userID, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CalendarToken{
+		Token:    token,
+		FeedPath: strings.Replace(CalendarFeedPath, ":token", token, 1),
+	}, nil
+}
+
+// entry describes event as calendar apps see it. A deleted event is a
+// cancellation, one sequence past the event's last version.
+func (s *calendarService) entry(event *models.Event) *models.CalendarEntry {
+	entry := &models.CalendarEntry{
+		UID:         fmt.Sprintf("event-%d@%s", event.ID, s.uidDomain),
+		Sequence:    event.Sequence,
+		Summary:     event.Name,
+		Description: event.Description,
+		Start:       event.DateTime,
+		UpdatedAt:   event.UpdatedAt,
+	}
+	if event.DeletedAt.Valid {
+		entry.Cancelled = true
+		entry.Sequence++
+		entry.UpdatedAt = event.DeletedAt.Time
+	}
+	return entry
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/validation"
+	"fmt"
+)
+
+// errImportRolledBack rolls back the import transaction for dry runs and
+// failed atomic imports.
+var errImportRolledBack = errors.New("import rolled back")
+
+// ImportEvents creates or updates the organizer's events from rows, matching
+// them by external reference. Every row is checked, including against the
+// database, so one pass reports all of a file's problems. Each row runs in
+// its own savepoint: a failed row never leaves a partial write behind, and
+// the others carry on. A dry run, or an atomic import with a failed row, then
+// rolls everything back.
+func (s *eventService) ImportEvents(ctx context.Context, organizerID int, rows []*models.EventImportRow, opts models.EventImportOptions) (*models.EventImportResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = models.ImportModeAtomic
+	}
+	result := &models.EventImportResult{Mode: opts.Mode, DryRun: opts.DryRun}
+
+	firstRow := make(map[string]int, len(rows))
+	for i, row := range rows {
+		rowResult := &models.EventImportRowResult{Row: i + 1, ExternalRef: row.ExternalRef}
+		rowResult.Errors = validateImportRow(row)
+		if first, ok := firstRow[row.ExternalRef]; ok && row.ExternalRef != "" {
+			rowResult.Errors = append(rowResult.Errors, validation.FieldError{
+				Field: "external_ref", Code: "DUPLICATE", Message: fmt.Sprintf("is already used by row %d", first),
+			})
+		} else {
+			firstRow[row.ExternalRef] = i + 1
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		for i, row := range rows {
+			rowResult := result.Rows[i]
+			if len(rowResult.Errors) > 0 {
+				continue
+			}
+
+			err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+				var err error
+				rowResult.Action, rowResult.EventID, err = s.importRow(ctx, organizerID, row)
+				return err
+			})
+			if err != nil {
+				if rowResult.Errors = rowErrors(err); rowResult.Errors == nil {
+					return fmt.Errorf("row %d: %w", rowResult.Row, err)
+				}
+			}
+		}
+
+		for _, rowResult := range result.Rows {
+			if len(rowResult.Errors) > 0 {
+				rowResult.Action, rowResult.EventID = models.ImportActionFailed, 0
+				result.Failed++
+			}
+		}
+		if result.Failed > 0 && opts.Mode == models.ImportModeAtomic {
+			for _, rowResult := range result.Rows {
+				if rowResult.Action != models.ImportActionFailed {
+					rowResult.Action, rowResult.EventID = models.ImportActionSkipped, 0
+				}
+			}
+			return errImportRolledBack
+		}
+
+		for _, rowResult := range result.Rows {
+			switch rowResult.Action {
+			case models.ImportActionCreated:
+				result.Created++
+			case models.ImportActionUpdated:
+				result.Updated++
+			case models.ImportActionUnchanged:
+				result.Unchanged++
+			}
+		}
+		if opts.DryRun {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, err
+	}
+
+	result.Applied = err == nil
+	return result, nil
+}
+
+// importRow creates the row's event, or updates the organizer's event with
+// the same external reference.
+func (s *eventService) importRow(ctx context.Context, organizerID int, row *models.EventImportRow) (string, int, error) {
+	existing, err := s.eventRepo.GetByExternalRef(ctx, organizerID, row.ExternalRef)
+	if apperrors.IsNotFound(err) {
+		ref := row.ExternalRef
+		event, err := s.createEvent(ctx, organizerID, &row.CreateEventRequest, &ref)
+		if err != nil {
+			return "", 0, err
+		}
+		return models.ImportActionCreated, event.ID, nil
+	}
+	if err == nil {
+		// hold it until the update, so the booked tickets can't move under us
+		existing, err = s.eventRepo.LockForUpdate(ctx, existing.ID)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	req, err := importUpdate(existing, row)
+	if err != nil {
+		return "", 0, err
+	}
+	if req == nil {
+		return models.ImportActionUnchanged, existing.ID, nil
+	}
+	if _, err := s.UpdateEvent(ctx, organizerID, existing.ID, req); err != nil {
+		return "", 0, err
+	}
+	return models.ImportActionUpdated, existing.ID, nil
+}
+
+// importUpdate returns the update that brings event in line with row, or nil
+// if they already match. The row's total_tickets is the event's capacity:
+// available tickets move by the same amount, and can't go below those
+// already booked.
+func importUpdate(event *models.Event, row *models.EventImportRow) (*models.UpdateEventRequest, error) {
+	req := &models.UpdateEventRequest{}
+	changed := false
+
+	if row.Name != event.Name {
+		req.Name, changed = &row.Name, true
+	}
+	if row.Description != event.Description {
+		req.Description, changed = &row.Description, true
+	}
+	if !row.DateTime.Equal(event.DateTime) {
+		req.DateTime, changed = &row.DateTime, true
+	}
+	if row.TotalTickets != event.Capacity {
+		booked := event.Capacity - event.TotalTickets
+		if row.TotalTickets < booked {
+			return nil, validation.Errors{{
+				Field: "total_tickets", Code: "BELOW_BOOKED",
+				Message: fmt.Sprintf("must be at least %d, the tickets already booked", booked),
+			}}
+		}
+		available := row.TotalTickets - booked
+		req.TotalTickets, changed = &available, true
+	}
+	if row.TicketPrice != event.TicketPrice {
+		req.TicketPrice, changed = &row.TicketPrice, true
+	}
+	if row.MaxTicketsPerOrder != event.MaxTicketsPerOrder {
+		req.MaxTicketsPerOrder, changed = &row.MaxTicketsPerOrder, true
+	}
+	if row.MaxTicketsPerUser != event.MaxTicketsPerUser {
+		req.MaxTicketsPerUser, changed = &row.MaxTicketsPerUser, true
+	}
+	if row.MaxTicketsPerPaymentInstrument != event.MaxTicketsPerPaymentInstrument {
+		req.MaxTicketsPerPaymentInstrument, changed = &row.MaxTicketsPerPaymentInstrument, true
+	}
+	if row.MaxTicketsPerEmailDomain != event.MaxTicketsPerEmailDomain {
+		req.MaxTicketsPerEmailDomain, changed = &row.MaxTicketsPerEmailDomain, true
+	}
+	// rows without ticket types, such as every CSV row, leave them alone
+	if row.TicketTypes != nil && !sameTicketTypes(event.TicketTypes, row.TicketTypes) {
+		req.TicketTypes, changed = &row.TicketTypes, true
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return req, nil
+}
+
+// sameTicketTypes reports whether reqs lists exactly the event's ticket
+// types, in any order.
+func sameTicketTypes(types []models.TicketType, reqs []models.TicketTypeRequest) bool {
+	if len(types) != len(reqs) {
+		return false
+	}
+	prices := make(map[string]float64, len(types))
+	for _, t := range types {
+		prices[t.Name] = t.Price
+	}
+	for _, req := range reqs {
+		if price, ok := prices[req.Name]; !ok || price != req.Price {
+			return false
+		}
+	}
+	return true
+}
+
+// validateImportRow returns what parsing found wrong with row, then every
+// rule it breaks.
+func validateImportRow(row *models.EventImportRow) []validation.FieldError {
+	errs := append([]validation.FieldError{}, row.ParseErrors...)
+	unreadable := make(map[string]bool, len(row.ParseErrors))
+	for _, fe := range row.ParseErrors {
+		unreadable[fe.Field] = true
+	}
+
+	for _, v := range []interface{}{row, &row.CreateEventRequest} {
+		var invalid validation.Errors
+		if err := validation.Struct(v); errors.As(err, &invalid) {
+			for _, fe := range invalid {
+				// a value that couldn't be read was left empty; say so once
+				if !unreadable[fe.Field] {
+					errs = append(errs, fe)
+				}
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// rowErrors turns an error from importing one row into the errors to report
+// for it. It returns nil for errors that aren't the row's fault, which abort
+// the whole import.
+func rowErrors(err error) []validation.FieldError {
+	var invalid validation.Errors
+	if errors.As(err, &invalid) {
+		return invalid
+	}
+	var domainErr *apperrors.Error
+	if errors.As(err, &domainErr) && domainErr.Kind != apperrors.KindInternal {
+		return []validation.FieldError{{Code: domainErr.Code, Message: domainErr.Message}}
+	}
+	return nil
+}
@@ -2,32 +2,72 @@ package service
 
 import (
 	"context"
+	"event-booking-be/internal/apperrors"
 	"event-booking-be/internal/models"
 	"event-booking-be/internal/repository"
 	"fmt"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type eventService struct {
-	eventRepo repository.EventRepository
+	eventRepo  repository.EventRepository
+	userRepo   repository.UserRepository
+	outboxRepo repository.OutboxRepository
+	auditRepo  repository.AuditRepository
+	db         *gorm.DB
 }
 
-func NewEventService(eventRepo repository.EventRepository) EventService {
+func NewEventService(
+	eventRepo repository.EventRepository,
+	userRepo repository.UserRepository,
+	outboxRepo repository.OutboxRepository,
+	auditRepo repository.AuditRepository,
+	db *gorm.DB,
+) EventService {
 	return &eventService{
-		eventRepo: eventRepo,
+		eventRepo:  eventRepo,
+		userRepo:   userRepo,
+		outboxRepo: outboxRepo,
+		auditRepo:  auditRepo,
+		db:         db,
 	}
 }
 
-func (s *eventService) CreateEvent(ctx context.Context, req *models.CreateEventRequest) (*models.Event, error) {
+func (s *eventService) CreateEvent(ctx context.Context, organizerID int, req *models.CreateEventRequest) (*models.Event, error) {
+	return s.createEvent(ctx, organizerID, req, nil)
+}
+
+// createEvent creates an event, with the organizer's external reference for
+// it if it was imported.
+func (s *eventService) createEvent(ctx context.Context, organizerID int, req *models.CreateEventRequest, externalRef *string) (*models.Event, error) {
 	event := &models.Event{
 		Name:         req.Name,
 		Description:  req.Description,
 		DateTime:     req.DateTime,
 		TotalTickets: req.TotalTickets,
+		Capacity:     req.TotalTickets,
 		TicketPrice:  req.TicketPrice,
+		OrganizerID:  &organizerID,
+		ExternalRef:  externalRef,
+
+		MaxTicketsPerOrder:             req.MaxTicketsPerOrder,
+		MaxTicketsPerUser:              req.MaxTicketsPerUser,
+		MaxTicketsPerPaymentInstrument: req.MaxTicketsPerPaymentInstrument,
+		MaxTicketsPerEmailDomain:       req.MaxTicketsPerEmailDomain,
+
+		TicketTypes: ticketTypes(req.TicketTypes),
 	}
 
-	if err := s.eventRepo.Create(ctx, event); err != nil {
-		return nil, fmt.Errorf("failed to create event: %w", err)
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.eventRepo.Create(ctx, event); err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+		return recordAudit(ctx, s.auditRepo, models.AuditEventCreated, models.AuditEntityEvent, event.ID, nil, event)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return event, nil
@@ -49,38 +89,137 @@ func (s *eventService) GetAllEvents(ctx context.Context) ([]*models.Event, error
 	return events, nil
 }
 
-func (s *eventService) UpdateEvent(ctx context.Context, id int, req *models.UpdateEventRequest) (*models.Event, error) {
-	event, err := s.eventRepo.GetByID(ctx, id)
+// UpdateEvent applies req to the event, locked for the rest of the
+// transaction so a booking can't change its tickets in between. Only the
+// columns req changes are written.
+func (s *eventService) UpdateEvent(ctx context.Context, userID, id int, req *models.UpdateEventRequest) (*models.Event, error) {
+	var event *models.Event
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		var err error
+		event, err = s.eventRepo.LockForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get event: %w", err)
+		}
+		if err := s.checkOrganizer(ctx, userID, event); err != nil {
+			return err
+		}
+
+		before := *event
+		var columns []string
+
+		if req.Name != nil {
+			event.Name = *req.Name
+			columns = append(columns, "name")
+		}
+		if req.Description != nil {
+			event.Description = *req.Description
+			columns = append(columns, "description")
+		}
+		if req.DateTime != nil {
+			event.DateTime = *req.DateTime
+			columns = append(columns, "date_time")
+		}
+		if req.TotalTickets != nil {
+			// resizing the event moves capacity by the same amount
+			event.Capacity += *req.TotalTickets - event.TotalTickets
+			event.TotalTickets = *req.TotalTickets
+			columns = append(columns, "total_tickets", "capacity")
+		}
+		if req.TicketPrice != nil {
+			event.TicketPrice = *req.TicketPrice
+			columns = append(columns, "ticket_price")
+		}
+		if req.MaxTicketsPerOrder != nil {
+			event.MaxTicketsPerOrder = *req.MaxTicketsPerOrder
+			columns = append(columns, "max_tickets_per_order")
+		}
+		if req.MaxTicketsPerUser != nil {
+			event.MaxTicketsPerUser = *req.MaxTicketsPerUser
+			columns = append(columns, "max_tickets_per_user")
+		}
+		if req.MaxTicketsPerPaymentInstrument != nil {
+			event.MaxTicketsPerPaymentInstrument = *req.MaxTicketsPerPaymentInstrument
+			columns = append(columns, "max_tickets_per_payment_instrument")
+		}
+		if req.MaxTicketsPerEmailDomain != nil {
+			event.MaxTicketsPerEmailDomain = *req.MaxTicketsPerEmailDomain
+			columns = append(columns, "max_tickets_per_email_domain")
+		}
+		if req.TicketTypes != nil {
+			event.TicketTypes = ticketTypes(*req.TicketTypes)
+			if err := s.eventRepo.ReplaceTicketTypes(ctx, id, event.TicketTypes); err != nil {
+				return fmt.Errorf("failed to update ticket types: %w", err)
+			}
+		}
+		// calendar apps only take an update with a higher sequence
+		if event.Name != before.Name || event.Description != before.Description || !event.DateTime.Equal(before.DateTime) {
+			event.Sequence++
+			columns = append(columns, "sequence")
+		}
+
+		if err := s.eventRepo.Update(ctx, id, event, columns); err != nil {
+			return fmt.Errorf("failed to update event: %w", err)
+		}
+
+		if err := recordAudit(ctx, s.auditRepo, models.AuditEventUpdated, models.AuditEntityEvent, id, &before, event); err != nil {
+			return err
+		}
+
+		return recordOutboxEvent(ctx, s.outboxRepo, models.AggregateTypeEvent, event.ID, models.DomainEventEventUpdated, models.EventUpdatedPayload{
+			EventID:          event.ID,
+			Name:             event.Name,
+			DateTime:         event.DateTime,
+			PreviousDateTime: before.DateTime,
+			TotalTickets:     event.TotalTickets,
+			TicketPrice:      event.TicketPrice,
+			OccurredAt:       time.Now(),
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("event not found: %w", err)
+		return nil, err
 	}
 
-	if req.Name != nil {
-		event.Name = *req.Name
-	}
-	if req.Description != nil {
-		event.Description = *req.Description
-	}
-	if req.DateTime != nil {
-		event.DateTime = *req.DateTime
-	}
-	if req.TotalTickets != nil {
-		event.TotalTickets = *req.TotalTickets
+	return event, nil
+}
+
+func (s *eventService) DeleteEvent(ctx context.Context, userID, id int) error {
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get event: %w", err)
 	}
-	if req.TicketPrice != nil {
-		event.TicketPrice = *req.TicketPrice
+	if err := s.checkOrganizer(ctx, userID, event); err != nil {
+		return err
 	}
 
-	if err := s.eventRepo.Update(ctx, id, event); err != nil {
-		return nil, fmt.Errorf("failed to update event: %w", err)
-	}
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.eventRepo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete event: %w", err)
+		}
+		return recordAudit(ctx, s.auditRepo, models.AuditEventDeleted, models.AuditEntityEvent, id, event, nil)
+	})
+}
 
-	return event, nil
+// ticketTypes turns requested ticket types into the rows to store.
+func ticketTypes(reqs []models.TicketTypeRequest) []models.TicketType {
+	if len(reqs) == 0 {
+		return nil
+	}
+	types := make([]models.TicketType, len(reqs))
+	for i, req := range reqs {
+		types[i] = models.TicketType{Name: req.Name, Price: req.Price}
+	}
+	return types
 }
 
-func (s *eventService) DeleteEvent(ctx context.Context, id int) error {
-	if err := s.eventRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete event: %w", err)
+// checkOrganizer lets only the event's organizer or an admin change it. Anyone
+// else is told the event doesn't exist, so its ID gives nothing away.
+func (s *eventService) checkOrganizer(ctx context.Context, userID int, event *models.Event) error {
+	allowed, err := isOrganizerOrAdmin(ctx, s.userRepo, userID, event)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return apperrors.ErrEventNotFound
 	}
 	return nil
 }
@@ -3,29 +3,91 @@ package service
 import (
 	"context"
 	"event-booking-be/internal/models"
+	"event-booking-be/internal/realtime"
 )
 
 type EventService interface {
-	CreateEvent(ctx context.Context, req *models.CreateEventRequest) (*models.Event, error)
+	CreateEvent(ctx context.Context, organizerID int, req *models.CreateEventRequest) (*models.Event, error)
 	GetEvent(ctx context.Context, id int) (*models.Event, error)
 	GetAllEvents(ctx context.Context) ([]*models.Event, error)
-	UpdateEvent(ctx context.Context, id int, req *models.UpdateEventRequest) (*models.Event, error)
-	DeleteEvent(ctx context.Context, id int) error
+	UpdateEvent(ctx context.Context, userID, id int, req *models.UpdateEventRequest) (*models.Event, error)
+	DeleteEvent(ctx context.Context, userID, id int) error
 	GetEventStatistics(ctx context.Context, eventID int) (*models.EventStatistics, error)
+	ImportEvents(ctx context.Context, organizerID int, rows []*models.EventImportRow, opts models.EventImportOptions) (*models.EventImportResult, error)
 }
 
 type BookingService interface {
 	CreateBooking(ctx context.Context, userID int, req *models.CreateBookingRequest) (*models.Booking, error)
-	GetBooking(ctx context.Context, id int) (*models.BookingWithDetails, error)
+	GetBooking(ctx context.Context, userID, id int) (*models.BookingWithDetails, error)
+	LookupBooking(ctx context.Context, id int) (*models.BookingWithDetails, error)
+	GetBookingHistory(ctx context.Context, userID, id int) ([]*models.BookingStatusChange, error)
 	GetUserBookings(ctx context.Context, userID int) ([]*models.Booking, error)
-	ConfirmPayment(ctx context.Context, bookingID int) error
-	CancelBooking(ctx context.Context, bookingID int) error
+	ConfirmPayment(ctx context.Context, userID, bookingID int) error
+	CancelBooking(ctx context.Context, userID, bookingID int) error
+	ExpireBooking(ctx context.Context, bookingID int) error
 	ProcessExpiredBookings(ctx context.Context) error
+	ListBookings(ctx context.Context, filter models.BookingFilter) ([]*models.Booking, error)
+	ForceExpireBooking(ctx context.Context, bookingID int) error
+	ForceConfirmBooking(ctx context.Context, bookingID int) error
+	CheckIn(ctx context.Context, userID, bookingID int) (*models.Booking, error)
+	GetEventAttendees(ctx context.Context, eventID int, statuses []models.BookingStatus) ([]*models.Attendee, error)
+	ExportAttendees(ctx context.Context, userID, eventID int) (AttendeeStream, error)
+	RecountInventory(ctx context.Context, eventID int) (*models.InventoryRecount, error)
 }
 
+// AttendeeStream calls fn for each attendee in turn, stopping at the first
+// error fn returns.
+type AttendeeStream func(ctx context.Context, fn func(*models.Attendee) error) error
+
 type UserService interface {
 	CreateUser(ctx context.Context, req *models.CreateUserRequest) (*models.User, error)
 	GetUser(ctx context.Context, id int) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	Login(ctx context.Context, req *models.LoginRequest) (*models.User, error)
+	CreateAdmin(ctx context.Context, req *models.CreateUserRequest) (*models.User, error)
+}
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, organizerID int, req *models.CreateWebhookRequest) (*models.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context, organizerID int) ([]*models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, organizerID int, id int) (*models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, organizerID int, id int, req *models.UpdateWebhookRequest) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, organizerID int, id int) error
+	GetDeliveries(ctx context.Context, organizerID int, id int) ([]*models.WebhookDelivery, error)
+	SendTestEvent(ctx context.Context, organizerID int, id int) (*models.WebhookDelivery, error)
+	Dispatch(ctx context.Context, message *models.OutboxMessage) error
+	ProcessDeliveries(ctx context.Context) error
+}
+
+type NotificationService interface {
+	Dispatch(ctx context.Context, message *models.OutboxMessage) error
+	SendReminder(ctx context.Context, reminder *models.Reminder) error
+	SendEmail(ctx context.Context, payload *models.SendEmailPayload) error
+}
+
+type ReminderService interface {
+	Dispatch(ctx context.Context, message *models.OutboxMessage) error
+	ProcessDue(ctx context.Context) error
+}
+
+type AvailabilityService interface {
+	GetAvailability(ctx context.Context, eventID int) (*realtime.Availability, error)
+	Subscribe(eventID int) (*realtime.Subscription, error)
+	Dispatch(ctx context.Context, message *models.OutboxMessage) error
+}
+
+type AuditService interface {
+	ListAuditLogs(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error)
+}
+
+type AnalyticsService interface {
+	GetEventAnalytics(ctx context.Context, userID, eventID int, rng models.AnalyticsRange) (*models.EventAnalytics, error)
+	CompareEvents(ctx context.Context, userID int, rng models.AnalyticsRange) ([]*models.EventAnalyticsSummary, error)
+}
+
+type CalendarService interface {
+	GetEventEntry(ctx context.Context, eventID int) (*models.CalendarEntry, error)
+	GetUpcomingEntries(ctx context.Context) ([]*models.CalendarEntry, error)
+	GetUserEntries(ctx context.Context, token string) ([]*models.CalendarEntry, error)
+	IssueToken(ctx context.Context, userID int) (*models.CalendarToken, error)
 }
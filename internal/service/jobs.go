@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/tracing"
+	"fmt"
+	"time"
+)
+
+// scheduleJob serializes payload and enqueues a job to run at runAt, using the
+// transaction carried by ctx. uniqueKey makes scheduling idempotent.
+func scheduleJob(
+	ctx context.Context,
+	jobRepo repository.JobRepository,
+	jobType string,
+	payload interface{},
+	runAt time.Time,
+	uniqueKey string,
+) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s job: %w", jobType, err)
+	}
+
+	job := &models.Job{
+		Type:        jobType,
+		Payload:     string(data),
+		UniqueKey:   &uniqueKey,
+		RunAt:       runAt,
+		TraceParent: tracing.TraceParent(ctx),
+	}
+	if err := jobRepo.Create(ctx, job); err != nil {
+		return fmt.Errorf("failed to schedule %s job: %w", jobType, err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/notification"
+	"event-booking-be/internal/repository"
+	"fmt"
+	"time"
+)
+
+type notificationService struct {
+	bookingRepo repository.BookingRepository
+	eventRepo   repository.EventRepository
+	userRepo    repository.UserRepository
+	jobRepo     repository.JobRepository
+	renderer    *notification.Renderer
+	mailer      notification.Mailer
+}
+
+func NewNotificationService(
+	bookingRepo repository.BookingRepository,
+	eventRepo repository.EventRepository,
+	userRepo repository.UserRepository,
+	jobRepo repository.JobRepository,
+	renderer *notification.Renderer,
+	mailer notification.Mailer,
+) NotificationService {
+	return &notificationService{
+		bookingRepo: bookingRepo,
+		eventRepo:   eventRepo,
+		userRepo:    userRepo,
+		jobRepo:     jobRepo,
+		renderer:    renderer,
+		mailer:      mailer,
+	}
+}
+
+var bookingMessageTypes = map[string]string{
+	models.DomainEventBookingCreated:   notification.TypeBookingPending,
+	models.DomainEventBookingConfirmed: notification.TypeBookingConfirmed,
+	models.DomainEventBookingExpired:   notification.TypeBookingExpired,
+	models.DomainEventBookingCancelled: notification.TypeBookingCancelled,
+}
+
+// Dispatch turns a domain event into emails and queues them as jobs. It's
+// registered as an outbox sink, so booking operations never wait on the mail
+// server, and the emails commit with the message being marked published.
+func (s *notificationService) Dispatch(ctx context.Context, message *models.OutboxMessage) error {
+	if messageType, ok := bookingMessageTypes[message.EventType]; ok {
+		var payload models.BookingEventPayload
+		if err := json.Unmarshal([]byte(message.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid booking payload: %w", err)
+		}
+		return s.notifyBooking(ctx, message.ID, messageType, &payload)
+	}
+
+	if message.EventType == models.DomainEventEventUpdated {
+		var payload models.EventUpdatedPayload
+		if err := json.Unmarshal([]byte(message.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid event payload: %w", err)
+		}
+		return s.notifyEventChanged(ctx, message.ID, &payload)
+	}
+
+	return nil
+}
+
+func (s *notificationService) notifyBooking(ctx context.Context, messageID int64, messageType string, payload *models.BookingEventPayload) error {
+	user, err := s.userRepo.GetByID(ctx, payload.UserID)
+	if err != nil {
+		return skipMissing(err)
+	}
+	event, err := s.eventRepo.GetByID(ctx, payload.EventID)
+	if err != nil {
+		return skipMissing(err)
+	}
+
+	data := &notification.TemplateData{
+		UserName:      user.Name,
+		EventName:     event.Name,
+		EventDateTime: event.DateTime,
+		Reference:     models.BookingReference(payload.BookingID),
+		TicketCount:   payload.TicketCount,
+		TotalPrice:    payload.TotalPrice,
+		ExpiresAt:     payload.ExpiresAt,
+	}
+	if messageType == notification.TypeBookingConfirmed {
+		for i := 1; i <= payload.TicketCount; i++ {
+			data.Tickets = append(data.Tickets, fmt.Sprintf("%s-%02d", data.Reference, i))
+		}
+	}
+
+	return s.send(ctx, messageID, payload.BookingID, user, messageType, data)
+}
+
+// notifyEventChanged tells everyone holding a live booking that the event
+// moved. Edits that don't affect attendees (price, inventory) are ignored.
+func (s *notificationService) notifyEventChanged(ctx context.Context, messageID int64, payload *models.EventUpdatedPayload) error {
+	if payload.DateTime.Equal(payload.PreviousDateTime) {
+		return nil
+	}
+
+	bookings, err := s.bookingRepo.GetByEventID(ctx, payload.EventID)
+	if err != nil {
+		return fmt.Errorf("can't get event bookings: %w", err)
+	}
+
+	for _, booking := range bookings {
+		if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusConfirmed {
+			continue
+		}
+
+		user, err := s.userRepo.GetByID(ctx, booking.UserID)
+		if err != nil {
+			if skipMissing(err) == nil {
+				continue
+			}
+			return err
+		}
+
+		err = s.send(ctx, messageID, booking.ID, user, notification.TypeEventChanged, &notification.TemplateData{
+			UserName:         user.Name,
+			EventName:        payload.Name,
+			EventDateTime:    payload.DateTime,
+			PreviousDateTime: payload.PreviousDateTime,
+			Reference:        models.BookingReference(booking.ID),
+			TicketCount:      booking.TicketCount,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SendReminder sends the reminder email synchronously so the caller can record
+// whether it was delivered. It returns ErrNotConfirmed if the booking was
+// cancelled or expired after the reminder was claimed.
+func (s *notificationService) SendReminder(ctx context.Context, reminder *models.Reminder) error {
+	booking, err := s.bookingRepo.GetByID(ctx, reminder.BookingID)
+	if err != nil {
+		return err
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		return apperrors.ErrNotConfirmed
+	}
+	user, err := s.userRepo.GetByID(ctx, booking.UserID)
+	if err != nil {
+		return err
+	}
+	event, err := s.eventRepo.GetByID(ctx, reminder.EventID)
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.renderer.Render(user.Locale, notification.TypeEventReminder, &notification.TemplateData{
+		UserName:      user.Name,
+		EventName:     event.Name,
+		EventDateTime: event.DateTime,
+		Reference:     models.BookingReference(booking.ID),
+		TicketCount:   booking.TicketCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg.To = user.Email
+	return s.mailer.Send(ctx, msg)
+}
+
+// SendEmail delivers an email queued by Dispatch. It runs as a job, which the
+// queue retries with backoff if the mail server refuses it.
+func (s *notificationService) SendEmail(ctx context.Context, payload *models.SendEmailPayload) error {
+	return s.mailer.Send(ctx, &notification.Message{
+		To:      payload.To,
+		Subject: payload.Subject,
+		Text:    payload.Text,
+		HTML:    payload.HTML,
+	})
+}
+
+// send renders an email about bookingID for user and queues it as a job. The
+// job is keyed by the outbox message and booking, so a redelivered message
+// doesn't send the email twice.
+func (s *notificationService) send(ctx context.Context, messageID int64, bookingID int, user *models.User, messageType string, data *notification.TemplateData) error {
+	msg, err := s.renderer.Render(user.Locale, messageType, data)
+	if err != nil {
+		return err
+	}
+
+	return scheduleJob(ctx, s.jobRepo, models.JobTypeSendEmail, &models.SendEmailPayload{
+		To:      user.Email,
+		Subject: msg.Subject,
+		Text:    msg.Text,
+		HTML:    msg.HTML,
+	}, time.Now(), fmt.Sprintf("email:%d:%d", messageID, bookingID))
+}
+
+// skipMissing drops "not found" errors: there's nobody to notify about a
+// deleted user or event, and retrying won't change that.
+func skipMissing(err error) error {
+	if apperrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
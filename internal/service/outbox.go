@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/tracing"
+	"fmt"
+)
+
+// recordOutboxEvent serializes payload and appends it to the outbox using the
+// transaction carried by ctx.
+func recordOutboxEvent(
+	ctx context.Context,
+	outboxRepo repository.OutboxRepository,
+	aggregateType string,
+	aggregateID int,
+	eventType string,
+	payload interface{},
+) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	message := &models.OutboxMessage{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+		TraceParent:   tracing.TraceParent(ctx),
+	}
+	if err := outboxRepo.Create(ctx, message); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
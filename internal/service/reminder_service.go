@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/logging"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	reminderBatchSize  = 100
+	reminderLease      = 5 * time.Minute
+	reminderRetryDelay = 5 * time.Minute
+)
+
+type reminderService struct {
+	reminderRepo        repository.ReminderRepository
+	eventRepo           repository.EventRepository
+	notificationService NotificationService
+	offsets             []time.Duration
+	maxAttempts         int
+	logger              *slog.Logger
+}
+
+func NewReminderService(
+	reminderRepo repository.ReminderRepository,
+	eventRepo repository.EventRepository,
+	notificationService NotificationService,
+	offsets []time.Duration,
+	maxAttempts int,
+	logger *slog.Logger,
+) ReminderService {
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	return &reminderService{
+		reminderRepo:        reminderRepo,
+		eventRepo:           eventRepo,
+		notificationService: notificationService,
+		offsets:             offsets,
+		maxAttempts:         maxAttempts,
+		logger:              logger,
+	}
+}
+
+// Dispatch keeps the reminder schedule in step with bookings and events. It's
+// registered as an outbox sink, so schedule changes commit atomically with
+// the outbox message being marked as published.
+func (s *reminderService) Dispatch(ctx context.Context, message *models.OutboxMessage) error {
+	switch message.EventType {
+	case models.DomainEventBookingConfirmed:
+		var payload models.BookingEventPayload
+		if err := json.Unmarshal([]byte(message.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid booking payload: %w", err)
+		}
+		return s.schedule(ctx, &payload)
+
+	case models.DomainEventBookingCancelled, models.DomainEventBookingExpired:
+		if err := s.reminderRepo.CancelByBookingID(ctx, message.AggregateID); err != nil {
+			return fmt.Errorf("failed to cancel reminders: %w", err)
+		}
+		return nil
+
+	case models.DomainEventEventUpdated:
+		var payload models.EventUpdatedPayload
+		if err := json.Unmarshal([]byte(message.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid event payload: %w", err)
+		}
+		if payload.DateTime.Equal(payload.PreviousDateTime) {
+			return nil
+		}
+		return s.reschedule(ctx, payload.EventID, payload.DateTime)
+	}
+
+	return nil
+}
+
+func (s *reminderService) schedule(ctx context.Context, payload *models.BookingEventPayload) error {
+	event, err := s.eventRepo.GetByID(ctx, payload.EventID)
+	if err != nil {
+		return skipMissing(err)
+	}
+
+	now := time.Now()
+	var reminders []*models.Reminder
+	for _, offset := range s.offsets {
+		sendAt := event.DateTime.Add(-offset)
+		// booked too late for this one
+		if !sendAt.After(now) {
+			continue
+		}
+
+		reminders = append(reminders, &models.Reminder{
+			BookingID:     payload.BookingID,
+			OffsetMinutes: int(offset / time.Minute),
+			EventID:       event.ID,
+			SendAt:        sendAt,
+			Status:        models.ReminderStatusPending,
+		})
+	}
+
+	if err := s.reminderRepo.CreateMany(ctx, reminders); err != nil {
+		return fmt.Errorf("failed to schedule reminders: %w", err)
+	}
+	return nil
+}
+
+// reschedule moves the event's reminders to the new date. Reminders already
+// sent for the old date, or being sent right now, are sent again if their new
+// time is still ahead. A reminder claimed while this runs fails the dispatch,
+// and the outbox retry picks it up in its new state.
+func (s *reminderService) reschedule(ctx context.Context, eventID int, dateTime time.Time) error {
+	reminders, err := s.reminderRepo.GetByEventID(ctx, eventID)
+	if err != nil {
+		return fmt.Errorf("can't get event reminders: %w", err)
+	}
+
+	now := time.Now()
+	for _, reminder := range reminders {
+		sendAt := dateTime.Add(-time.Duration(reminder.OffsetMinutes) * time.Minute)
+
+		switch reminder.Status {
+		case models.ReminderStatusPending:
+		case models.ReminderStatusSent, models.ReminderStatusSending:
+			if !sendAt.After(now) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		rescheduled, err := s.reminderRepo.Reschedule(ctx, reminder, sendAt)
+		if err != nil {
+			return fmt.Errorf("failed to reschedule reminder: %w", err)
+		}
+		if !rescheduled {
+			return fmt.Errorf("reminder %d changed while being rescheduled", reminder.ID)
+		}
+	}
+
+	return nil
+}
+
+// ProcessDue sends reminders that are due. Each one is claimed with a
+// compare-and-set before sending, so with several replicas running only one
+// sends it. An instance that dies mid-send leaves the claim to lapse, after
+// which another instance picks the reminder up.
+func (s *reminderService) ProcessDue(ctx context.Context) error {
+	reminders, err := s.reminderRepo.GetDue(ctx, reminderBatchSize)
+	if err != nil {
+		return fmt.Errorf("can't fetch due reminders: %w", err)
+	}
+
+	for _, reminder := range reminders {
+		claimed, err := s.reminderRepo.Claim(ctx, reminder, reminderLease)
+		if err != nil {
+			return fmt.Errorf("failed to claim reminder: %w", err)
+		}
+		if !claimed {
+			continue
+		}
+
+		s.send(ctx, reminder)
+
+		logCtx := logging.With(ctx, "booking_id", reminder.BookingID, "event_id", reminder.EventID)
+		saved, err := s.reminderRepo.SaveOutcome(ctx, reminder)
+		if err != nil {
+			s.logger.ErrorContext(logCtx, "Failed to save reminder", "reminder_id", reminder.ID, "error", err)
+		} else if !saved {
+			s.logger.InfoContext(logCtx, "Reminder was cancelled or rescheduled while sending", "reminder_id", reminder.ID)
+		}
+	}
+
+	return nil
+}
+
+func (s *reminderService) send(ctx context.Context, reminder *models.Reminder) {
+	event, err := s.eventRepo.GetByID(ctx, reminder.EventID)
+	if err == nil {
+		if !event.DateTime.After(time.Now()) {
+			// the event already started
+			cancelReminder(reminder)
+			return
+		}
+		err = s.notificationService.SendReminder(ctx, reminder)
+	}
+	if apperrors.IsNotFound(err) || errors.Is(err, apperrors.ErrNotConfirmed) {
+		// event or booking deleted, or the booking cancelled or expired
+		cancelReminder(reminder)
+		return
+	}
+
+	if err != nil {
+		reminder.LastError = err.Error()
+		if reminder.Attempts >= s.maxAttempts {
+			reminder.Status = models.ReminderStatusFailed
+			reminder.ClaimedUntil = nil
+			return
+		}
+
+		retryAt := time.Now().Add(reminderRetryDelay)
+		reminder.Status = models.ReminderStatusPending
+		reminder.ClaimedUntil = &retryAt
+		return
+	}
+
+	now := time.Now()
+	reminder.Status = models.ReminderStatusSent
+	reminder.SentAt = &now
+	reminder.ClaimedUntil = nil
+	reminder.LastError = ""
+}
+
+func cancelReminder(reminder *models.Reminder) {
+	reminder.Status = models.ReminderStatusCancelled
+	reminder.ClaimedUntil = nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TraceBookingService wraps svc so every call gets its own span, nested
+// between the request span and the repository query spans.
+func TraceBookingService(svc BookingService) BookingService {
+	return &tracedBookingService{next: svc}
+}
+
+type tracedBookingService struct {
+	next BookingService
+}
+
+func bookingAttr(id int) attribute.KeyValue { return attribute.Int("booking.id", id) }
+func eventAttr(id int) attribute.KeyValue   { return attribute.Int("event.id", id) }
+
+func (s *tracedBookingService) CreateBooking(ctx context.Context, userID int, req *models.CreateBookingRequest) (booking *models.Booking, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.CreateBooking",
+		attribute.Int("user.id", userID), eventAttr(req.EventID), attribute.Int("booking.ticket_count", req.TicketCount))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CreateBooking(ctx, userID, req)
+}
+
+func (s *tracedBookingService) GetBooking(ctx context.Context, userID, id int) (booking *models.BookingWithDetails, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetBooking", attribute.Int("user.id", userID), bookingAttr(id))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetBooking(ctx, userID, id)
+}
+
+func (s *tracedBookingService) LookupBooking(ctx context.Context, id int) (booking *models.BookingWithDetails, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.LookupBooking", bookingAttr(id))
+	defer func() { tracing.End(span, err) }()
+	return s.next.LookupBooking(ctx, id)
+}
+
+func (s *tracedBookingService) GetBookingHistory(ctx context.Context, userID, id int) (history []*models.BookingStatusChange, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetBookingHistory", attribute.Int("user.id", userID), bookingAttr(id))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetBookingHistory(ctx, userID, id)
+}
+
+func (s *tracedBookingService) GetUserBookings(ctx context.Context, userID int) (bookings []*models.Booking, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetUserBookings", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetUserBookings(ctx, userID)
+}
+
+func (s *tracedBookingService) ConfirmPayment(ctx context.Context, userID, bookingID int) (err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.ConfirmPayment", attribute.Int("user.id", userID), bookingAttr(bookingID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ConfirmPayment(ctx, userID, bookingID)
+}
+
+func (s *tracedBookingService) CancelBooking(ctx context.Context, userID, bookingID int) (err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.CancelBooking", attribute.Int("user.id", userID), bookingAttr(bookingID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CancelBooking(ctx, userID, bookingID)
+}
+
+func (s *tracedBookingService) ExpireBooking(ctx context.Context, bookingID int) (err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.ExpireBooking", bookingAttr(bookingID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ExpireBooking(ctx, bookingID)
+}
+
+func (s *tracedBookingService) ProcessExpiredBookings(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.ProcessExpiredBookings")
+	defer func() { tracing.End(span, err) }()
+	return s.next.ProcessExpiredBookings(ctx)
+}
+
+func (s *tracedBookingService) ListBookings(ctx context.Context, filter models.BookingFilter) (bookings []*models.Booking, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.ListBookings")
+	defer func() { tracing.End(span, err) }()
+	return s.next.ListBookings(ctx, filter)
+}
+
+func (s *tracedBookingService) ForceExpireBooking(ctx context.Context, bookingID int) (err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.ForceExpireBooking", bookingAttr(bookingID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ForceExpireBooking(ctx, bookingID)
+}
+
+func (s *tracedBookingService) ForceConfirmBooking(ctx context.Context, bookingID int) (err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.ForceConfirmBooking", bookingAttr(bookingID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ForceConfirmBooking(ctx, bookingID)
+}
+
+func (s *tracedBookingService) CheckIn(ctx context.Context, userID, bookingID int) (booking *models.Booking, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.CheckIn", attribute.Int("user.id", userID), bookingAttr(bookingID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CheckIn(ctx, userID, bookingID)
+}
+
+func (s *tracedBookingService) GetEventAttendees(ctx context.Context, eventID int, statuses []models.BookingStatus) (attendees []*models.Attendee, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetEventAttendees", eventAttr(eventID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetEventAttendees(ctx, eventID, statuses)
+}
+
+func (s *tracedBookingService) ExportAttendees(ctx context.Context, userID, eventID int) (stream AttendeeStream, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.ExportAttendees", eventAttr(eventID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ExportAttendees(ctx, userID, eventID)
+}
+
+func (s *tracedBookingService) RecountInventory(ctx context.Context, eventID int) (recount *models.InventoryRecount, err error) {
+	ctx, span := tracing.Start(ctx, "BookingService.RecountInventory", eventAttr(eventID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.RecountInventory(ctx, eventID)
+}
+
+// TraceEventService wraps svc so every call gets its own span.
+func TraceEventService(svc EventService) EventService {
+	return &tracedEventService{next: svc}
+}
+
+type tracedEventService struct {
+	next EventService
+}
+
+func (s *tracedEventService) CreateEvent(ctx context.Context, organizerID int, req *models.CreateEventRequest) (event *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.CreateEvent", attribute.Int("organizer.id", organizerID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CreateEvent(ctx, organizerID, req)
+}
+
+func (s *tracedEventService) GetEvent(ctx context.Context, id int) (event *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetEvent", eventAttr(id))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetEvent(ctx, id)
+}
+
+func (s *tracedEventService) GetAllEvents(ctx context.Context) (events []*models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetAllEvents")
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetAllEvents(ctx)
+}
+
+func (s *tracedEventService) UpdateEvent(ctx context.Context, userID, id int, req *models.UpdateEventRequest) (event *models.Event, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.UpdateEvent", eventAttr(id))
+	defer func() { tracing.End(span, err) }()
+	return s.next.UpdateEvent(ctx, userID, id, req)
+}
+
+func (s *tracedEventService) DeleteEvent(ctx context.Context, userID, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "EventService.DeleteEvent", eventAttr(id))
+	defer func() { tracing.End(span, err) }()
+	return s.next.DeleteEvent(ctx, userID, id)
+}
+
+func (s *tracedEventService) GetEventStatistics(ctx context.Context, eventID int) (stats *models.EventStatistics, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.GetEventStatistics", eventAttr(eventID))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetEventStatistics(ctx, eventID)
+}
+
+func (s *tracedEventService) ImportEvents(ctx context.Context, organizerID int, rows []*models.EventImportRow, opts models.EventImportOptions) (result *models.EventImportResult, err error) {
+	ctx, span := tracing.Start(ctx, "EventService.ImportEvents", attribute.Int("organizer.id", organizerID), attribute.Int("import.rows", len(rows)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ImportEvents(ctx, organizerID, rows, opts)
+}
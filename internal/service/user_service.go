@@ -2,18 +2,25 @@ package service
 
 import (
 	"context"
+	"event-booking-be/internal/apperrors"
 	"event-booking-be/internal/models"
 	"event-booking-be/internal/repository"
 	"fmt"
+
+	"gorm.io/gorm"
 )
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo  repository.UserRepository
+	auditRepo repository.AuditRepository
+	db        *gorm.DB
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, auditRepo repository.AuditRepository, db *gorm.DB) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:  userRepo,
+		auditRepo: auditRepo,
+		db:        db,
 	}
 }
 
@@ -21,16 +28,23 @@ func (s *userService) CreateUser(ctx context.Context, req *models.CreateUserRequ
 	// Check if email already exists
 	existingUser, _ := s.userRepo.GetByEmail(ctx, req.Email)
 	if existingUser != nil {
-		return nil, fmt.Errorf("email already exists")
+		return nil, apperrors.ErrEmailTaken
+	}
+
+	locale := req.Locale
+	if locale == "" {
+		locale = "en"
 	}
 
 	user := &models.User{
-		Name:  req.Name,
-		Email: req.Email,
+		Name:   req.Name,
+		Email:  req.Email,
+		Locale: locale,
+		Role:   models.UserRoleUser,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+	if err := s.createUser(ctx, user); err != nil {
+		return nil, err
 	}
 
 	return user, nil
@@ -47,15 +61,65 @@ func (s *userService) GetUser(ctx context.Context, id int) (*models.User, error)
 func (s *userService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	return user, nil
 }
 
 func (s *userService) Login(ctx context.Context, req *models.LoginRequest) (*models.User, error) {
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if apperrors.IsNotFound(err) {
+		return nil, apperrors.ErrInvalidCredentials
+	}
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// CreateAdmin creates an admin user, or promotes the existing user with the
+// same email. It is only reachable from the admin CLI.
+func (s *userService) CreateAdmin(ctx context.Context, req *models.CreateUserRequest) (*models.User, error) {
+	existingUser, _ := s.userRepo.GetByEmail(ctx, req.Email)
+	if existingUser != nil {
+		before := *existingUser
+		err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+			if err := s.userRepo.UpdateRole(ctx, existingUser.ID, models.UserRoleAdmin); err != nil {
+				return fmt.Errorf("failed to promote user: %w", err)
+			}
+			existingUser.Role = models.UserRoleAdmin
+			return recordAudit(ctx, s.auditRepo, models.AuditUserPromoted, models.AuditEntityUser, existingUser.ID, &before, existingUser)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return existingUser, nil
+	}
+
+	locale := req.Locale
+	if locale == "" {
+		locale = "en"
+	}
+
+	user := &models.User{
+		Name:   req.Name,
+		Email:  req.Email,
+		Locale: locale,
+		Role:   models.UserRoleAdmin,
 	}
+
+	if err := s.createUser(ctx, user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
+
+func (s *userService) createUser(ctx context.Context, user *models.User) error {
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return recordAudit(ctx, s.auditRepo, models.AuditUserCreated, models.AuditEntityUser, user.ID, nil, user)
+	})
+}
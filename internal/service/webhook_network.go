@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// errWebhookAddressBlocked is returned for webhook hosts on loopback,
+// private, link-local or other non-public addresses. Letting organizers
+// reach those would turn the delivery worker into a proxy into our network.
+var errWebhookAddressBlocked = errors.New("webhook address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range, which net/netip doesn't
+// count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// checkWebhookHost resolves host and rejects it unless every address it
+// resolves to is public.
+func checkWebhookHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("can't resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !isPublicAddr(addr) {
+			return errWebhookAddressBlocked
+		}
+	}
+	return nil
+}
+
+// newWebhookClient returns the client deliveries go out through. Unless
+// allowPrivate is set, its dialer refuses non-public addresses, checked on
+// the address actually dialled so DNS changes after subscribing and redirects
+// can't get around it.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !isPublicAddr(addrPort.Addr()) {
+				return errWebhookAddressBlocked
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would dial for us, out of reach of the check above
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,490 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookResponseLimit   = 1024
+	webhookDeliveryBatch   = 50
+	webhookDeliveryHistory = 100
+)
+
+type WebhookConfig struct {
+	MaxAttempts          int
+	DisableAfterFailures int
+	RetryBaseDelay       time.Duration
+	Timeout              time.Duration
+	// AllowPrivateNetworks lets subscriptions reach loopback and private
+	// addresses. Only meant for local development.
+	AllowPrivateNetworks bool
+	// ClaimTimeout is how long a fetched batch is held before another worker
+	// may pick it up again; it has to cover sending the whole batch.
+	ClaimTimeout time.Duration
+	Logger       *slog.Logger
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	eventRepo   repository.EventRepository
+	db          *gorm.DB
+	client      *http.Client
+	cfg         WebhookConfig
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	eventRepo repository.EventRepository,
+	db *gorm.DB,
+	client *http.Client,
+	cfg WebhookConfig,
+) WebhookService {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.DisableAfterFailures <= 0 {
+		cfg.DisableAfterFailures = 20
+	}
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = 30 * time.Second
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = webhookDeliveryBatch*timeout + time.Minute
+	}
+	if client == nil {
+		client = newWebhookClient(timeout, cfg.AllowPrivateNetworks)
+	}
+
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		eventRepo:   eventRepo,
+		db:          db,
+		client:      client,
+		cfg:         cfg,
+	}
+}
+
+// SignWebhookPayload computes the v1 signature sent in the X-Webhook-Signature
+// header: hex(HMAC-SHA256(secret, "<timestamp>.<body>")).
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, organizerID int, req *models.CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	if err := s.validateURL(ctx, req.URL); err != nil {
+		return nil, err
+	}
+
+	eventTypes, err := normalizeWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+	}
+
+	subscription := &models.WebhookSubscription{
+		OrganizerID: organizerID,
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		Active:      true,
+	}
+
+	if err := s.webhookRepo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (s *webhookService) GetSubscriptions(ctx context.Context, organizerID int) ([]*models.WebhookSubscription, error) {
+	subscriptions, err := s.webhookRepo.GetSubscriptionsByOrganizer(ctx, organizerID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (s *webhookService) GetSubscription(ctx context.Context, organizerID int, id int) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// don't reveal other organizers' subscriptions
+	if subscription.OrganizerID != organizerID {
+		return nil, apperrors.ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+func (s *webhookService) UpdateSubscription(ctx context.Context, organizerID int, id int, req *models.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	subscription, err := s.GetSubscription(ctx, organizerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := s.validateURL(ctx, *req.URL); err != nil {
+			return nil, err
+		}
+		subscription.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		if subscription.EventTypes, err = normalizeWebhookEventTypes(*req.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if req.Active != nil {
+		subscription.Active = *req.Active
+		if subscription.Active {
+			// re-enabling starts with a clean slate
+			subscription.ConsecutiveFailures = 0
+			subscription.DisabledAt = nil
+		}
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, organizerID int, id int) error {
+	if _, err := s.GetSubscription(ctx, organizerID, id); err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.DeleteSubscription(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, organizerID int, id int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(ctx, organizerID, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookRepo.GetDeliveriesBySubscription(ctx, id, webhookDeliveryHistory)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// SendTestEvent delivers a webhook.test event right away. Test deliveries are
+// attempted once and don't count towards disabling the subscription.
+func (s *webhookService) SendTestEvent(ctx context.Context, organizerID int, id int) (*models.WebhookDelivery, error) {
+	subscription, err := s.GetSubscription(ctx, organizerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"subscription_id": subscription.ID,
+		"message":         "This is a test event",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventType:      models.WebhookEventTest,
+		Payload:        string(payload),
+		Status:         models.WebhookDeliveryPending,
+	}
+	if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to create test delivery: %w", err)
+	}
+
+	s.attempt(ctx, subscription, delivery)
+	if delivery.Status != models.WebhookDeliverySucceeded {
+		delivery.Status = models.WebhookDeliveryFailed
+	}
+
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, fmt.Errorf("failed to save test delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// Dispatch fans a domain event out to the active subscriptions of the event's
+// organizer. It's registered as an outbox sink.
+func (s *webhookService) Dispatch(ctx context.Context, message *models.OutboxMessage) error {
+	if !isWebhookEventType(message.EventType) {
+		return nil
+	}
+
+	eventID := message.AggregateID
+	if message.AggregateType == models.AggregateTypeBooking {
+		var payload models.BookingEventPayload
+		if err := json.Unmarshal([]byte(message.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid booking payload: %w", err)
+		}
+		eventID = payload.EventID
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return skipMissing(err)
+	}
+	if event.OrganizerID == nil {
+		return nil
+	}
+
+	subscriptions, err := s.webhookRepo.GetActiveSubscriptionsByOrganizer(ctx, *event.OrganizerID)
+	if err != nil {
+		return fmt.Errorf("can't get webhook subscriptions: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscribedTo(subscription, message.EventType) {
+			continue
+		}
+
+		delivery := &models.WebhookDelivery{
+			SubscriptionID:  subscription.ID,
+			OutboxMessageID: &message.ID,
+			EventType:       message.EventType,
+			Payload:         message.Payload,
+			Status:          models.WebhookDeliveryPending,
+		}
+		if err := s.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to queue webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ProcessDeliveries sends one batch of due deliveries, rescheduling failures
+// with exponential backoff until MaxAttempts is reached. The batch is claimed
+// in a short transaction and sent outside it, so a slow receiver doesn't hold
+// the rows locked and a failed save can't roll back a POST that went out.
+func (s *webhookService) ProcessDeliveries(ctx context.Context) error {
+	var deliveries []*models.WebhookDelivery
+	err := repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		var err error
+		deliveries, err = s.webhookRepo.GetDueDeliveries(ctx, webhookDeliveryBatch)
+		if err != nil {
+			return fmt.Errorf("can't fetch webhook deliveries: %w", err)
+		}
+
+		ids := make([]int64, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		if err := s.webhookRepo.ClaimDeliveries(ctx, ids, time.Now().Add(s.cfg.ClaimTimeout)); err != nil {
+			return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, delivery := range deliveries {
+		if err := s.deliver(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("webhook delivery %d: %w", delivery.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver sends a claimed delivery and saves the outcome. If it returns early
+// with an error, the claim runs out and the delivery is tried again later.
+func (s *webhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	subscription, err := s.webhookRepo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil && !apperrors.IsNotFound(err) {
+		return fmt.Errorf("can't get webhook subscription: %w", err)
+	}
+	if err != nil || !subscription.Active {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = "subscription deleted or disabled"
+		if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to save webhook delivery: %w", err)
+		}
+		return nil
+	}
+
+	s.attempt(ctx, subscription, delivery)
+
+	return repository.Transaction(ctx, s.db, func(ctx context.Context) error {
+		if err := s.recordOutcome(ctx, subscription, delivery); err != nil {
+			return err
+		}
+		if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to save webhook delivery: %w", err)
+		}
+		return nil
+	})
+}
+
+func (s *webhookService) recordOutcome(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) error {
+	if delivery.Status == models.WebhookDeliverySucceeded {
+		return s.webhookRepo.RecordSubscriptionSuccess(ctx, subscription.ID)
+	}
+
+	if delivery.Attempts >= s.cfg.MaxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+	} else {
+		delivery.NextAttemptAt = time.Now().Add(s.cfg.RetryBaseDelay << (delivery.Attempts - 1))
+	}
+
+	s.cfg.Logger.WarnContext(ctx, "Webhook delivery failed",
+		"delivery_id", delivery.ID, "subscription_id", subscription.ID,
+		"attempt", delivery.Attempts, "error", delivery.LastError)
+
+	return s.webhookRepo.RecordSubscriptionFailure(ctx, subscription.ID, s.cfg.DisableAfterFailures)
+}
+
+// attempt performs a single HTTP delivery and records the response on delivery.
+func (s *webhookService) attempt(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         delivery.ID,
+		"type":       delivery.EventType,
+		"created_at": delivery.CreatedAt,
+		"data":       json.RawMessage(delivery.Payload),
+	})
+	if err != nil {
+		delivery.LastError = err.Error()
+		return
+	}
+
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.LastError = err.Error()
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "event-booking-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set(webhookSignatureHeader, fmt.Sprintf("t=%d,v1=%s",
+		timestamp, SignWebhookPayload(subscription.Secret, timestamp, body)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		delivery.LastError = err.Error()
+		return
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = string(responseBody)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		delivery.LastError = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+		return
+	}
+
+	now := time.Now()
+	delivery.Status = models.WebhookDeliverySucceeded
+	delivery.DeliveredAt = &now
+	delivery.LastError = ""
+}
+
+// validateURL rejects anything but http(s) URLs and, unless private networks
+// are allowed, hosts that resolve to non-public addresses. The delivery client
+// checks again when dialling, since DNS can change after subscribing.
+func (s *webhookService) validateURL(ctx context.Context, raw string) error {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apperrors.ErrInvalidWebhook.Withf("invalid webhook url")
+	}
+	if s.cfg.AllowPrivateNetworks {
+		return nil
+	}
+
+	if err := checkWebhookHost(ctx, u.Hostname()); err != nil {
+		if errors.Is(err, errWebhookAddressBlocked) {
+			return apperrors.ErrInvalidWebhook.Withf("webhook url must resolve to a public address")
+		}
+		return apperrors.ErrInvalidWebhook.Withf("can't resolve webhook host %q", u.Hostname())
+	}
+	return nil
+}
+
+func normalizeWebhookEventTypes(eventTypes []string) (string, error) {
+	for _, eventType := range eventTypes {
+		if !isWebhookEventType(eventType) {
+			return "", apperrors.ErrInvalidWebhook.Withf("invalid webhook event type %q", eventType)
+		}
+	}
+	return strings.Join(eventTypes, ","), nil
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, supported := range models.WebhookEventTypes {
+		if eventType == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func subscribedTo(subscription *models.WebhookSubscription, eventType string) bool {
+	if subscription.EventTypes == "" {
+		return true
+	}
+	for _, subscribed := range strings.Split(subscription.EventTypes, ",") {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
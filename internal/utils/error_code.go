@@ -16,7 +16,72 @@ const (
 	BOOKING_ALREADY_CANCELLED = "BOOKING_ALREADY_CANCELLED"
 	BOOKING_ALREADY_CONFIRMED = "BOOKING_ALREADY_CONFIRMED"
 	BOOKING_EXPIRED          = "BOOKING_EXPIRED"
+	BOOKING_NOT_EXPIRED      = "BOOKING_NOT_EXPIRED"
 	BOOKING_CANCEL_FAILED    = "BOOKING_CANCEL_FAILED"
+	BOOKING_ORDER_LIMIT_EXCEEDED      = "BOOKING_ORDER_LIMIT_EXCEEDED"
+	BOOKING_USER_LIMIT_EXCEEDED       = "BOOKING_USER_LIMIT_EXCEEDED"
+	BOOKING_INSTRUMENT_LIMIT_EXCEEDED = "BOOKING_INSTRUMENT_LIMIT_EXCEEDED"
+	BOOKING_DOMAIN_LIMIT_EXCEEDED     = "BOOKING_DOMAIN_LIMIT_EXCEEDED"
+	BOOKING_INSTRUMENT_REQUIRED       = "BOOKING_INSTRUMENT_REQUIRED"
+	BOOKING_UNKNOWN_TICKET_TYPE       = "BOOKING_UNKNOWN_TICKET_TYPE"
+	BOOKING_NOT_CONFIRMED             = "BOOKING_NOT_CONFIRMED"
+	BOOKING_ALREADY_CHECKED_IN        = "BOOKING_ALREADY_CHECKED_IN"
+	WEBHOOK_NOT_FOUND        = "WEBHOOK_NOT_FOUND"
+	WEBHOOK_INVALID_ID       = "WEBHOOK_INVALID_ID"
+	WEBHOOK_INVALID_REQUEST  = "WEBHOOK_INVALID_REQUEST"
+	ANALYTICS_INVALID_TIMEZONE = "ANALYTICS_INVALID_TIMEZONE"
+	ANALYTICS_INVALID_RANGE    = "ANALYTICS_INVALID_RANGE"
+	EXPORT_INVALID_COLUMN      = "EXPORT_INVALID_COLUMN"
+	IMPORT_INVALID_FILE        = "IMPORT_INVALID_FILE"
+	STREAM_UNAVAILABLE       = "STREAM_UNAVAILABLE"
+	RATE_LIMITED             = "RATE_LIMITED"
 	INVALID_REQUEST_BODY = "INVALID_REQUEST_BODY"
+	INVALID_QUERY        = "INVALID_QUERY"
+	FORBIDDEN            = "FORBIDDEN"
+	VALIDATION_FAILED    = "VALIDATION_FAILED"
 	INTERNAL_SERVER_ERROR = "INTERNAL_SERVER_ERROR"
 )
+
+// ErrorCodes lists every code above; the API reference publishes it as the
+// ErrorCode enum.
+var ErrorCodes = []string{
+	USER_NOT_FOUND,
+	USER_ALREADY_EXISTS,
+	USER_INVALID_CREDENTIALS,
+	EVENT_NOT_FOUND,
+	EVENT_INVALID_ID,
+	EVENT_CREATE_FAILED,
+	EVENT_UPDATE_FAILED,
+	EVENT_DELETE_FAILED,
+	BOOKING_NOT_FOUND,
+	BOOKING_INVALID_ID,
+	BOOKING_CREATE_FAILED,
+	BOOKING_NOT_ENOUGH_TICKETS,
+	BOOKING_ALREADY_CANCELLED,
+	BOOKING_ALREADY_CONFIRMED,
+	BOOKING_EXPIRED,
+	BOOKING_NOT_EXPIRED,
+	BOOKING_CANCEL_FAILED,
+	BOOKING_ORDER_LIMIT_EXCEEDED,
+	BOOKING_USER_LIMIT_EXCEEDED,
+	BOOKING_INSTRUMENT_LIMIT_EXCEEDED,
+	BOOKING_DOMAIN_LIMIT_EXCEEDED,
+	BOOKING_INSTRUMENT_REQUIRED,
+	BOOKING_UNKNOWN_TICKET_TYPE,
+	BOOKING_NOT_CONFIRMED,
+	BOOKING_ALREADY_CHECKED_IN,
+	WEBHOOK_NOT_FOUND,
+	WEBHOOK_INVALID_ID,
+	WEBHOOK_INVALID_REQUEST,
+	ANALYTICS_INVALID_TIMEZONE,
+	ANALYTICS_INVALID_RANGE,
+	EXPORT_INVALID_COLUMN,
+	IMPORT_INVALID_FILE,
+	STREAM_UNAVAILABLE,
+	RATE_LIMITED,
+	INVALID_REQUEST_BODY,
+	INVALID_QUERY,
+	FORBIDDEN,
+	VALIDATION_FAILED,
+	INTERNAL_SERVER_ERROR,
+}
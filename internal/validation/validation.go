@@ -0,0 +1,123 @@
+// Package validation enforces the `validate` tags on request DTOs, plus the
+// domain rules registered here, and reports every failing field at once.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is one failed rule. Field is the JSON name of the field and
+// Code a stable identifier clients can branch on.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors is returned when a value fails validation.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// report fields by the name clients send
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	// future: a time strictly after now
+	v.RegisterValidation("future", func(fl validator.FieldLevel) bool {
+		t, ok := fl.Field().Interface().(time.Time)
+		return ok && t.After(time.Now())
+	})
+
+	return v
+}
+
+// Struct validates s and returns Errors listing every failed rule, or nil.
+func Struct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	result := make(Errors, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		result[i] = fieldError(fe)
+	}
+	return result
+}
+
+func fieldError(fe validator.FieldError) FieldError {
+	// Namespace is "CreateEventRequest.date_time"; drop the type name
+	field := fe.Namespace()
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+
+	code, message := describe(fe)
+	return FieldError{Field: field, Code: code, Message: message}
+}
+
+func describe(fe validator.FieldError) (string, string) {
+	isString := fe.Kind() == reflect.String
+	isList := fe.Kind() == reflect.Slice || fe.Kind() == reflect.Array
+	switch fe.Tag() {
+	case "required":
+		return "REQUIRED", "is required"
+	case "min", "gte":
+		if isString {
+			return "TOO_SHORT", fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		if isList {
+			return "TOO_FEW", fmt.Sprintf("must have at least %s items", fe.Param())
+		}
+		return "TOO_SMALL", fmt.Sprintf("must be at least %s", fe.Param())
+	case "max", "lte":
+		if isString {
+			return "TOO_LONG", fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		if isList {
+			return "TOO_MANY", fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return "TOO_LARGE", fmt.Sprintf("must be at most %s", fe.Param())
+	case "email":
+		return "INVALID_EMAIL", "must be a valid email address"
+	case "url":
+		return "INVALID_URL", "must be a valid URL"
+	case "unique":
+		return "DUPLICATE", fmt.Sprintf("must not repeat a %s", strings.ToLower(fe.Param()))
+	case "oneof":
+		return "INVALID_CHOICE", fmt.Sprintf("must be one of: %s", fe.Param())
+	case "future":
+		return "NOT_IN_FUTURE", "must be in the future"
+	case "datetime":
+		return "INVALID_DATETIME", fmt.Sprintf("must be a timestamp formatted as %s", fe.Param())
+	}
+	return "INVALID", fmt.Sprintf("failed the %s rule", fe.Tag())
+}
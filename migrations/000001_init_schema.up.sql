@@ -0,0 +1,58 @@
+-- Baseline schema. Uses IF NOT EXISTS throughout so databases created by the
+-- old AutoMigrate startup are adopted as-is.
+
+CREATE TABLE IF NOT EXISTS users (
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    locale     VARCHAR(10)  NOT NULL DEFAULT 'en',
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS events (
+    id            BIGSERIAL PRIMARY KEY,
+    name          VARCHAR(255)   NOT NULL,
+    description   TEXT,
+    date_time     TIMESTAMPTZ    NOT NULL,
+    total_tickets BIGINT         NOT NULL,
+    ticket_price  DECIMAL(10, 2) NOT NULL,
+    organizer_id  BIGINT,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    deleted_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_organizer_id ON events (organizer_id);
+CREATE INDEX IF NOT EXISTS idx_events_deleted_at ON events (deleted_at);
+
+CREATE TABLE IF NOT EXISTS bookings (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT         NOT NULL REFERENCES users (id),
+    event_id     BIGINT         NOT NULL REFERENCES events (id),
+    ticket_count BIGINT         NOT NULL,
+    total_price  DECIMAL(10, 2) NOT NULL,
+    status       VARCHAR(20)    NOT NULL,
+    expires_at   TIMESTAMPTZ    NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    expired_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ,
+    deleted_at   TIMESTAMPTZ
+);
+
+-- columns added after the first AutoMigrate deployments
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS organizer_id BIGINT;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS expired_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_user_id ON bookings (user_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_event_id ON bookings (event_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_status ON bookings (status);
+CREATE INDEX IF NOT EXISTS idx_bookings_expires_at ON bookings (expires_at);
+CREATE INDEX IF NOT EXISTS idx_bookings_deleted_at ON bookings (deleted_at);
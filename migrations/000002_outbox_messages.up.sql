@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id              BIGSERIAL PRIMARY KEY,
+    aggregate_type  VARCHAR(50)  NOT NULL,
+    aggregate_id    BIGINT       NOT NULL,
+    event_type      VARCHAR(100) NOT NULL,
+    payload         TEXT         NOT NULL,
+    attempts        BIGINT       NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ  NOT NULL,
+    published_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate ON outbox_messages (aggregate_type, aggregate_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_next_attempt_at ON outbox_messages (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_published_at ON outbox_messages (published_at);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id                   BIGSERIAL PRIMARY KEY,
+    organizer_id         BIGINT        NOT NULL,
+    url                  VARCHAR(2048) NOT NULL,
+    secret               VARCHAR(255)  NOT NULL,
+    event_types          TEXT,
+    active               BOOLEAN       NOT NULL DEFAULT TRUE,
+    consecutive_failures BIGINT        NOT NULL DEFAULT 0,
+    disabled_at          TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ,
+    updated_at           TIMESTAMPTZ,
+    deleted_at           TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_organizer_id ON webhook_subscriptions (organizer_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted_at ON webhook_subscriptions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id                BIGSERIAL PRIMARY KEY,
+    subscription_id   BIGINT       NOT NULL,
+    outbox_message_id BIGINT,
+    event_type        VARCHAR(100) NOT NULL,
+    payload           TEXT         NOT NULL,
+    status            VARCHAR(20)  NOT NULL,
+    attempts          BIGINT       NOT NULL DEFAULT 0,
+    next_attempt_at   TIMESTAMPTZ  NOT NULL,
+    response_status   BIGINT,
+    response_body     TEXT,
+    last_error        TEXT,
+    delivered_at      TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_delivery_message ON webhook_deliveries (subscription_id, outbox_message_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS reminders (
+    id             BIGSERIAL PRIMARY KEY,
+    booking_id     BIGINT      NOT NULL,
+    offset_minutes BIGINT      NOT NULL,
+    event_id       BIGINT      NOT NULL,
+    send_at        TIMESTAMPTZ NOT NULL,
+    status         VARCHAR(20) NOT NULL,
+    attempts       BIGINT      NOT NULL DEFAULT 0,
+    claimed_until  TIMESTAMPTZ,
+    sent_at        TIMESTAMPTZ,
+    last_error     TEXT,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminder_booking_offset ON reminders (booking_id, offset_minutes);
+CREATE INDEX IF NOT EXISTS idx_reminders_event_id ON reminders (event_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_send_at ON reminders (send_at);
+CREATE INDEX IF NOT EXISTS idx_reminders_status ON reminders (status);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id           BIGSERIAL PRIMARY KEY,
+    type         VARCHAR(100) NOT NULL,
+    payload      TEXT         NOT NULL,
+    unique_key   VARCHAR(255),
+    run_at       TIMESTAMPTZ  NOT NULL,
+    status       VARCHAR(20)  NOT NULL,
+    attempts     BIGINT       NOT NULL DEFAULT 0,
+    max_attempts BIGINT       NOT NULL DEFAULT 5,
+    locked_by    VARCHAR(255),
+    locked_until TIMESTAMPTZ,
+    last_error   TEXT,
+    completed_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_unique_key ON jobs (unique_key);
+CREATE INDEX IF NOT EXISTS idx_jobs_run_at ON jobs (run_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs (status);
@@ -0,0 +1,13 @@
+-- total_tickets counts down as tickets are booked; capacity keeps the event's
+-- size so inventory can be recounted from bookings.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS capacity BIGINT NOT NULL DEFAULT 0;
+
+UPDATE events e
+SET capacity = e.total_tickets + COALESCE((
+    SELECT SUM(b.ticket_count)
+    FROM bookings b
+    WHERE b.event_id = e.id
+      AND b.status IN ('PENDING', 'CONFIRMED')
+      AND b.deleted_at IS NULL
+), 0)
+WHERE e.capacity = 0;
@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS trace_parent;
+ALTER TABLE outbox_messages DROP COLUMN IF EXISTS trace_parent;
@@ -0,0 +1,2 @@
+ALTER TABLE outbox_messages ADD COLUMN IF NOT EXISTS trace_parent VARCHAR(55);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS trace_parent VARCHAR(55);
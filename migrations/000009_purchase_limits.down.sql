@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_bookings_payment_instrument;
+ALTER TABLE bookings DROP COLUMN IF EXISTS payment_instrument;
+
+ALTER TABLE events DROP COLUMN IF EXISTS max_tickets_per_email_domain;
+ALTER TABLE events DROP COLUMN IF EXISTS max_tickets_per_payment_instrument;
+ALTER TABLE events DROP COLUMN IF EXISTS max_tickets_per_user;
+ALTER TABLE events DROP COLUMN IF EXISTS max_tickets_per_order;
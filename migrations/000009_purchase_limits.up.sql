@@ -0,0 +1,8 @@
+-- Per-event purchase limits; 0 means no limit.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS max_tickets_per_order BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS max_tickets_per_user BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS max_tickets_per_payment_instrument BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS max_tickets_per_email_domain BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS payment_instrument VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_bookings_payment_instrument ON bookings (payment_instrument);
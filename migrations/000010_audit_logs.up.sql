@@ -0,0 +1,31 @@
+-- Append-only record of service mutations.
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id          BIGSERIAL PRIMARY KEY,
+    actor_type  VARCHAR(20)  NOT NULL,
+    actor_id    BIGINT,
+    actor_name  VARCHAR(255),
+    action      VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(50)  NOT NULL,
+    entity_id   BIGINT       NOT NULL,
+    changes     TEXT         NOT NULL,
+    request_id  VARCHAR(128),
+    created_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_request_id ON audit_logs (request_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+-- Entries are never rewritten: refuse updates and deletes at the database.
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+CREATE TRIGGER audit_logs_append_only
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
@@ -0,0 +1,35 @@
+-- Every booking status change, with its reason and actor.
+CREATE TABLE IF NOT EXISTS booking_status_history (
+    id          BIGSERIAL PRIMARY KEY,
+    booking_id  BIGINT       NOT NULL,
+    from_status VARCHAR(20),
+    to_status   VARCHAR(20)  NOT NULL,
+    reason      VARCHAR(255) NOT NULL,
+    actor_type  VARCHAR(20)  NOT NULL,
+    actor_id    BIGINT,
+    actor_name  VARCHAR(255),
+    created_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_status_history_booking_id ON booking_status_history (booking_id);
+
+-- Rebuild what the timestamps on existing bookings still tell.
+INSERT INTO booking_status_history (booking_id, to_status, reason, actor_type, created_at)
+SELECT id, 'PENDING', 'created', 'system', created_at FROM bookings;
+
+INSERT INTO booking_status_history (booking_id, from_status, to_status, reason, actor_type, created_at)
+SELECT id, 'PENDING', 'CONFIRMED', 'payment confirmed', 'system', confirmed_at FROM bookings WHERE confirmed_at IS NOT NULL;
+
+-- Before the EXPIRED status, the expiry worker cancelled unpaid bookings, so
+-- an unpaid booking cancelled once its hold ran out was an expiry. Who
+-- cancelled the rest isn't recorded.
+INSERT INTO booking_status_history (booking_id, from_status, to_status, reason, actor_type, created_at)
+SELECT id,
+       CASE WHEN confirmed_at IS NOT NULL THEN 'CONFIRMED' ELSE 'PENDING' END,
+       'CANCELLED',
+       CASE WHEN confirmed_at IS NULL AND cancelled_at >= expires_at THEN 'expired by worker' ELSE 'cancelled' END,
+       'system', cancelled_at
+FROM bookings WHERE cancelled_at IS NOT NULL;
+
+INSERT INTO booking_status_history (booking_id, from_status, to_status, reason, actor_type, created_at)
+SELECT id, 'PENDING', 'EXPIRED', 'expired by worker', 'system', expired_at FROM bookings WHERE expired_at IS NOT NULL;
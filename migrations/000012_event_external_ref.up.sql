@@ -0,0 +1,7 @@
+-- The ID an event has in the organizer's own systems, so bulk imports can
+-- update events they created before instead of duplicating them.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS external_ref VARCHAR(100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_events_organizer_external_ref
+    ON events (organizer_id, external_ref)
+    WHERE external_ref IS NOT NULL AND deleted_at IS NULL;
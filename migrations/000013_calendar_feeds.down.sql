@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_calendar_token_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS calendar_token_hash;
+
+ALTER TABLE events DROP COLUMN IF EXISTS sequence;
@@ -0,0 +1,7 @@
+-- Bumped when an event changes in a way calendar apps show, so they replace
+-- their copy (the iCalendar SEQUENCE).
+ALTER TABLE events ADD COLUMN IF NOT EXISTS sequence BIGINT NOT NULL DEFAULT 0;
+
+-- SHA-256 of the token in a user's private calendar feed URL.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS calendar_token_hash VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_calendar_token_hash ON users (calendar_token_hash);
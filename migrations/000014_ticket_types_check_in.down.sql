@@ -0,0 +1,4 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS checked_in_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS ticket_type;
+
+DROP TABLE IF EXISTS ticket_types;
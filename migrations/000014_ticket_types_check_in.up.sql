@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS ticket_types (
+    id       BIGSERIAL PRIMARY KEY,
+    event_id BIGINT        NOT NULL,
+    name     VARCHAR(50)   NOT NULL,
+    price    DECIMAL(10,2) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_types_event_name ON ticket_types (event_id, name);
+
+-- Empty for bookings of the event's default ticket.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS ticket_type VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ;
@@ -0,0 +1,9 @@
+// Package migrations holds the versioned SQL schema migrations. Files are
+// named NNNNNN_name.up.sql / NNNNNN_name.down.sql and embedded into the binary
+// so `api migrate up` needs nothing but the database URL.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmin_ForceTransitionsAndRecount(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	// zero timeout: bookings are past their payment window straight away
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 0, testLogger)
+
+	event := &models.Event{
+		Name:         "Ops Event",
+		DateTime:     time.Now().Add(24 * time.Hour),
+		TotalTickets: 20,
+		TicketPrice:  15.0,
+	}
+	require.NoError(t, eventRepo.Create(ctx, event))
+	assert.Equal(t, 20, event.Capacity)
+
+	user := &models.User{Name: "Ops", Email: "ops-admin@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	late, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 3})
+	require.NoError(t, err)
+	stuck, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+	require.NoError(t, err)
+
+	// a late payment can't be confirmed normally, but can be forced
+	assert.Error(t, bookingService.ConfirmPayment(ctx, user.ID, late.ID))
+	require.NoError(t, bookingService.ForceConfirmBooking(ctx, late.ID))
+	assert.Error(t, bookingService.ForceExpireBooking(ctx, late.ID), "confirmed bookings can't be expired")
+
+	require.NoError(t, bookingService.ForceExpireBooking(ctx, stuck.ID))
+	stored, err := bookingRepo.GetByID(ctx, stuck.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusExpired, stored.Status)
+
+	attendees, err := bookingService.GetEventAttendees(ctx, event.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, attendees, 1)
+	assert.Equal(t, late.ID, attendees[0].BookingID)
+	assert.Equal(t, "ops-admin@test.com", attendees[0].Email)
+
+	// inventory is consistent: recounting changes nothing
+	recount, err := bookingService.RecountInventory(ctx, event.ID)
+	require.NoError(t, err)
+	assert.False(t, recount.Changed)
+	assert.Equal(t, 17, recount.After)
+
+	// drift the counter and recount it back
+	require.NoError(t, eventRepo.SetAvailableTickets(ctx, event.ID, 2))
+	recount, err = bookingService.RecountInventory(ctx, event.ID)
+	require.NoError(t, err)
+	assert.True(t, recount.Changed)
+	assert.Equal(t, 2, recount.Before)
+	assert.Equal(t, 17, recount.After)
+	assert.Equal(t, 3, recount.HeldTickets)
+
+	restored, err := eventRepo.GetByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 17, restored.TotalTickets)
+}
+
+func TestAdmin_CreateAdminPromotesExistingUser(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	userService := service.NewUserService(repository.NewUserRepository(db), repository.NewAuditRepository(db), db)
+
+	user, err := userService.CreateUser(ctx, &models.CreateUserRequest{Name: "Pat", Email: "pat-admin@test.com"})
+	require.NoError(t, err)
+	assert.Equal(t, models.UserRoleUser, user.Role)
+
+	admin, err := userService.CreateAdmin(ctx, &models.CreateUserRequest{Name: "Pat", Email: "pat-admin@test.com"})
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, admin.ID)
+
+	stored, err := userService.GetUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.UserRoleAdmin, stored.Role)
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func TestAnalytics_EventSeries(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	analyticsService := service.NewAnalyticsService(eventRepo, bookingRepo, userRepo)
+
+	organizer := &models.User{Name: "Orla", Email: "orla-analytics@test.com"}
+	require.NoError(t, userRepo.Create(ctx, organizer))
+	buyer := &models.User{Name: "Ben", Email: "ben-analytics@test.com"}
+	require.NoError(t, userRepo.Create(ctx, buyer))
+
+	event := &models.Event{Name: "Spring Gala", DateTime: *at("2026-04-01T20:00:00Z"), TotalTickets: 100, TicketPrice: 10, OrganizerID: &organizer.ID}
+	require.NoError(t, eventRepo.Create(ctx, event))
+	other := &models.Event{Name: "Someone Else's", DateTime: *at("2026-04-02T20:00:00Z"), TotalTickets: 10, TicketPrice: 10}
+	require.NoError(t, eventRepo.Create(ctx, other))
+
+	for _, b := range []*models.Booking{
+		// created before the range, paid inside it: a sale, not part of the cohort
+		{TicketCount: 1, TotalPrice: 10, Status: models.BookingStatusConfirmed, CreatedAt: *at("2026-02-28T12:00:00Z"), ConfirmedAt: at("2026-03-01T01:00:00Z")},
+		{TicketCount: 2, TotalPrice: 20, TicketType: "VIP", Status: models.BookingStatusConfirmed, CreatedAt: *at("2026-03-01T10:00:00Z"), ConfirmedAt: at("2026-03-01T10:05:00Z")},
+		{TicketCount: 1, TotalPrice: 10, Status: models.BookingStatusExpired, CreatedAt: *at("2026-03-01T23:30:00Z"), ExpiredAt: at("2026-03-02T00:00:00Z")},
+		{TicketCount: 3, TotalPrice: 30, Status: models.BookingStatusCancelled, CreatedAt: *at("2026-03-02T09:00:00Z"), CancelledAt: at("2026-03-02T09:30:00Z")},
+	} {
+		b.UserID, b.EventID, b.ExpiresAt = buyer.ID, event.ID, b.CreatedAt.Add(15*time.Minute)
+		require.NoError(t, bookingRepo.Create(ctx, b))
+	}
+
+	rng := models.AnalyticsRange{From: *at("2026-03-01T00:00:00Z"), To: *at("2026-03-03T00:00:00Z")}
+	analytics, err := analyticsService.GetEventAnalytics(ctx, organizer.ID, event.ID, rng)
+	require.NoError(t, err)
+	assert.Equal(t, models.AnalyticsDay, analytics.Interval)
+	assert.Equal(t, "UTC", analytics.Timezone)
+	require.Len(t, analytics.Series, 2)
+
+	day1, day2 := analytics.Series[0], analytics.Series[1]
+	assert.True(t, day1.Start.Equal(*at("2026-03-01T00:00:00Z")))
+	assert.Equal(t, 2, day1.BookingsCreated)
+	assert.Equal(t, 2, day1.BookingsConfirmed)
+	assert.Equal(t, 3, day1.TicketsSold)
+	assert.InDelta(t, 30.0, day1.Revenue, 0.001)
+	assert.Equal(t, 1, day2.BookingsCreated)
+	assert.Equal(t, 1, day2.BookingsExpired)
+	assert.Equal(t, 1, day2.BookingsCancelled)
+
+	totals := analytics.Totals
+	assert.Equal(t, 3, totals.BookingsCreated)
+	assert.InDelta(t, 1.0/3, totals.ConversionRate, 0.001)
+	assert.InDelta(t, 1.0/3, totals.ExpiryRate, 0.001)
+	assert.InDelta(t, 1.0/3, totals.CancellationRate, 0.001)
+	assert.Equal(t, []models.TicketTypeSales{
+		{TicketType: "", TicketsSold: 1, Revenue: 10},
+		{TicketType: "VIP", TicketsSold: 2, Revenue: 20},
+	}, analytics.ByTicketType)
+
+	// in New York (UTC-5) the expiry at midnight UTC still falls on March 1st
+	rng.Timezone = "America/New_York"
+	analytics, err = analyticsService.GetEventAnalytics(ctx, organizer.ID, event.ID, rng)
+	require.NoError(t, err)
+	require.Len(t, analytics.Series, 3)
+	assert.Equal(t, "2026-02-28T00:00:00-05:00", analytics.Series[0].Start.Format(time.RFC3339))
+	assert.Equal(t, 1, analytics.Series[1].BookingsExpired)
+	assert.Equal(t, 0, analytics.Series[2].BookingsExpired)
+
+	rng = models.AnalyticsRange{Interval: models.AnalyticsHour, From: *at("2026-03-01T10:00:00Z"), To: *at("2026-03-01T12:00:00Z")}
+	analytics, err = analyticsService.GetEventAnalytics(ctx, organizer.ID, event.ID, rng)
+	require.NoError(t, err)
+	require.Len(t, analytics.Series, 2)
+	assert.Equal(t, 1, analytics.Series[0].BookingsConfirmed)
+
+	// organizers only see their own events
+	_, err = analyticsService.GetEventAnalytics(ctx, buyer.ID, event.ID, rng)
+	assert.True(t, errors.Is(err, apperrors.ErrEventNotFound), "got %v", err)
+
+	summaries, err := analyticsService.CompareEvents(ctx, organizer.ID, models.AnalyticsRange{From: *at("2026-03-01T00:00:00Z"), To: *at("2026-03-03T00:00:00Z")})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, event.ID, summaries[0].EventID)
+	assert.Equal(t, 3, summaries[0].Totals.TicketsSold)
+	assert.InDelta(t, 0.03, summaries[0].SellThrough, 0.0001)
+}
+
+func TestAnalytics_RejectsBadRanges(t *testing.T) {
+	db := setupTestDB(t)
+	analyticsService := service.NewAnalyticsService(repository.NewEventRepository(db), repository.NewBookingRepository(db),
+		repository.NewUserRepository(db))
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, &handler.BookingHandler{}, &handler.WebhookHandler{},
+		&handler.AvailabilityHandler{}, &handler.AuditHandler{}, handler.NewAnalyticsHandler(analyticsService), &handler.CalendarHandler{},
+		nil, nil, routes.RateLimits{}).Setup(app)
+
+	get := func(query string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/organizer/events/analytics?"+query, nil)
+		req.Header.Set("X-User-ID", strconv.Itoa(1))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, get(""))
+	assert.Equal(t, http.StatusBadRequest, get("tz=Mars/Olympus_Mons"))
+	assert.Equal(t, http.StatusBadRequest, get("interval=hour&from=2026-01-01T00:00:00Z&to=2026-03-01T00:00:00Z"))
+	assert.Equal(t, http.StatusBadRequest, get("from=2026-03-01T00:00:00Z&to=2026-02-01T00:00:00Z"))
+	assert.Equal(t, http.StatusUnprocessableEntity, get("interval=week"))
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"event-booking-be/internal/audit"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditedEvent(t *testing.T, eventService service.EventService, ctx context.Context) *models.Event {
+	event, err := eventService.CreateEvent(ctx, 0, &models.CreateEventRequest{
+		Name:         "Audited Event",
+		DateTime:     time.Now().Add(24 * time.Hour),
+		TotalTickets: 10,
+		TicketPrice:  5,
+	})
+	require.NoError(t, err)
+	return event
+}
+
+func TestAudit_RecordsDiffActorAndRequest(t *testing.T) {
+	db := setupTestDB(t)
+	auditRepo := repository.NewAuditRepository(db)
+	eventService := service.NewEventService(repository.NewEventRepository(db), repository.NewUserRepository(db), repository.NewOutboxRepository(db), auditRepo, db)
+
+	ctx := audit.WithRequestID(audit.WithActor(context.Background(), audit.User(42)), "req-audit-1")
+	event := auditedEvent(t, eventService, ctx)
+
+	name := "Renamed Event"
+	_, err := eventService.UpdateEvent(ctx, 0, event.ID, &models.UpdateEventRequest{Name: &name})
+	require.NoError(t, err)
+
+	entries, err := auditRepo.List(ctx, models.AuditLogFilter{EntityType: models.AuditEntityEvent, EntityID: event.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	updated := entries[0]
+	assert.Equal(t, models.AuditEventUpdated, updated.Action)
+	assert.Equal(t, audit.ActorUser, updated.ActorType)
+	require.NotNil(t, updated.ActorID)
+	assert.Equal(t, 42, *updated.ActorID)
+	assert.Equal(t, "req-audit-1", updated.RequestID)
+
+	var changes map[string]audit.Change
+	require.NoError(t, json.Unmarshal([]byte(updated.Changes), &changes))
+	assert.Equal(t, map[string]audit.Change{
+		"name": {Before: "Audited Event", After: "Renamed Event"},
+	}, changes)
+
+	assert.Equal(t, models.AuditEventCreated, entries[1].Action)
+}
+
+func TestAudit_RolledBackChangesLeaveNoEntry(t *testing.T) {
+	db := setupTestDB(t)
+	auditRepo := repository.NewAuditRepository(db)
+	eventService := service.NewEventService(repository.NewEventRepository(db), repository.NewUserRepository(db), repository.NewOutboxRepository(db), auditRepo, db)
+	ctx := context.Background()
+	event := auditedEvent(t, eventService, ctx)
+
+	abort := errors.New("abort")
+	err := repository.Transaction(ctx, db, func(ctx context.Context) error {
+		name := "Never Saved"
+		if _, err := eventService.UpdateEvent(ctx, 0, event.ID, &models.UpdateEventRequest{Name: &name}); err != nil {
+			return err
+		}
+		return abort
+	})
+	require.ErrorIs(t, err, abort)
+
+	entries, err := auditRepo.List(ctx, models.AuditLogFilter{EntityType: models.AuditEntityEvent, EntityID: event.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditEventCreated, entries[0].Action)
+	assert.Equal(t, audit.ActorSystem, entries[0].ActorType)
+}
+
+func TestAudit_AdminEndpoint(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	auditRepo := repository.NewAuditRepository(db)
+	userService := service.NewUserService(repository.NewUserRepository(db), auditRepo, db)
+	eventService := service.NewEventService(repository.NewEventRepository(db), repository.NewUserRepository(db), repository.NewOutboxRepository(db), auditRepo, db)
+
+	admin, err := userService.CreateAdmin(ctx, &models.CreateUserRequest{Name: "Root", Email: "root-audit@test.com"})
+	require.NoError(t, err)
+	member, err := userService.CreateUser(ctx, &models.CreateUserRequest{Name: "Member", Email: "member-audit@test.com"})
+	require.NoError(t, err)
+	event := auditedEvent(t, eventService, ctx)
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, &handler.BookingHandler{},
+		&handler.WebhookHandler{}, &handler.AvailabilityHandler{}, handler.NewAuditHandler(service.NewAuditService(auditRepo)), &handler.AnalyticsHandler{}, &handler.CalendarHandler{},
+		userService, nil, routes.RateLimits{}).Setup(app)
+
+	get := func(userID int, query url.Values) (*http.Response, []*models.AuditLog) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit-logs?"+query.Encode(), nil)
+		req.Header.Set("X-User-ID", strconv.Itoa(userID))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		var body struct {
+			Data []*models.AuditLog `json:"data"`
+		}
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		}
+		return resp, body.Data
+	}
+
+	resp, _ := get(member.ID, nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	byEvent := url.Values{"entity_type": {models.AuditEntityEvent}, "entity_id": {strconv.Itoa(event.ID)}}
+	resp, entries := get(admin.ID, byEvent)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditEventCreated, entries[0].Action)
+
+	byEvent.Set("from", time.Now().UTC().Add(time.Hour).Format(time.RFC3339))
+	resp, entries = get(admin.ID, byEvent)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, entries)
+
+	byEvent.Set("from", time.Now().UTC().Add(-time.Hour).Format(time.RFC3339))
+	byEvent.Set("to", time.Now().UTC().Add(time.Hour).Format(time.RFC3339))
+	_, entries = get(admin.ID, byEvent)
+	assert.Len(t, entries, 1)
+
+	resp, _ = get(admin.ID, url.Values{"from": {"yesterday"}})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp, entries = get(admin.ID, url.Values{"entity_type": {models.AuditEntityUser}, "entity_id": {fmt.Sprint(admin.ID)}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditUserCreated, entries[0].Action)
+}
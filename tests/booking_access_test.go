@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingAccess_OtherUsersGetNotFound(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	bookingService := service.NewBookingService(repository.NewBookingRepository(db), repository.NewEventRepository(db), userRepo,
+		repository.NewOutboxRepository(db), repository.NewJobRepository(db), auditRepo, db, 15, testLogger)
+	eventService := service.NewEventService(repository.NewEventRepository(db), userRepo, repository.NewOutboxRepository(db), auditRepo, db)
+
+	newUser := func(email string) *models.User {
+		user := &models.User{Name: email, Email: email}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+	owner := newUser("owner-access@test.com")
+	stranger := newUser("stranger-access@test.com")
+	organizer := newUser("organizer-access@test.com")
+	admin := newUser("admin-access@test.com")
+	require.NoError(t, userRepo.UpdateRole(ctx, admin.ID, models.UserRoleAdmin))
+
+	event, err := eventService.CreateEvent(ctx, organizer.ID, &models.CreateEventRequest{
+		Name: "Private Party", DateTime: time.Now().Add(time.Hour), TotalTickets: 10, TicketPrice: 1,
+	})
+	require.NoError(t, err)
+	booking, err := bookingService.CreateBooking(ctx, owner.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+
+	// the service refuses strangers as if the booking did not exist
+	_, err = bookingService.GetBooking(ctx, stranger.ID, booking.ID)
+	assert.True(t, errors.Is(err, apperrors.ErrBookingNotFound), "got %v", err)
+	_, err = bookingService.GetBookingHistory(ctx, stranger.ID, booking.ID)
+	assert.True(t, errors.Is(err, apperrors.ErrBookingNotFound), "got %v", err)
+	assert.True(t, errors.Is(bookingService.ConfirmPayment(ctx, stranger.ID, booking.ID), apperrors.ErrBookingNotFound))
+	assert.True(t, errors.Is(bookingService.CancelBooking(ctx, stranger.ID, booking.ID), apperrors.ErrBookingNotFound))
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, handler.NewBookingHandler(bookingService),
+		&handler.WebhookHandler{}, &handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{}, nil, nil, routes.RateLimits{}).Setup(app)
+
+	call := func(method, path string, userID int) int {
+		req := httptest.NewRequest(method, fmt.Sprintf("/api/v1/bookings/%d%s", booking.ID, path), nil)
+		req.Header.Set("X-User-ID", strconv.Itoa(userID))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, ""},
+		{http.MethodGet, "/history"},
+		{http.MethodPost, "/confirm"},
+		{http.MethodPost, "/cancel"},
+	} {
+		assert.Equal(t, http.StatusNotFound, call(route.method, route.path, stranger.ID), "%s %s", route.method, route.path)
+	}
+
+	// the owner, the event's organizer and admins get through
+	for _, userID := range []int{owner.ID, organizer.ID, admin.ID} {
+		assert.Equal(t, http.StatusOK, call(http.MethodGet, "", userID), "user %d", userID)
+		assert.Equal(t, http.StatusOK, call(http.MethodGet, "/history", userID), "user %d", userID)
+	}
+	assert.Equal(t, http.StatusOK, call(http.MethodPost, "/confirm", owner.ID))
+
+	stored, err := bookingService.GetBooking(ctx, owner.ID, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusConfirmed, stored.Status)
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/audit"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingHistory_RecordsEveryTransition(t *testing.T) {
+	db := setupTestDB(t)
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	// zero timeout: bookings can be expired straight away
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, repository.NewUserRepository(db), repository.NewOutboxRepository(db),
+		repository.NewJobRepository(db), repository.NewAuditRepository(db), db, 0, testLogger)
+
+	event := &models.Event{Name: "History Event", DateTime: time.Now().Add(time.Hour), TotalTickets: 10, TicketPrice: 1}
+	require.NoError(t, eventRepo.Create(context.Background(), event))
+	user := &models.User{Name: "Hana", Email: "hana-history@test.com"}
+	require.NoError(t, repository.NewUserRepository(db).Create(context.Background(), user))
+	userCtx := audit.WithActor(context.Background(), audit.User(user.ID))
+
+	cancelled, err := bookingService.CreateBooking(userCtx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+	require.NoError(t, bookingService.CancelBooking(userCtx, user.ID, cancelled.ID))
+
+	expired, err := bookingService.CreateBooking(userCtx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+	require.NoError(t, bookingService.ExpireBooking(context.Background(), expired.ID))
+
+	history, err := bookingService.GetBookingHistory(userCtx, user.ID, cancelled.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, models.BookingStatus(""), history[0].FromStatus)
+	assert.Equal(t, models.BookingStatusPending, history[0].ToStatus)
+	assert.Equal(t, models.BookingReasonCreated, history[0].Reason)
+	assert.Equal(t, models.BookingStatusPending, history[1].FromStatus)
+	assert.Equal(t, models.BookingStatusCancelled, history[1].ToStatus)
+	assert.Equal(t, models.BookingReasonUserCancelled, history[1].Reason)
+	assert.Equal(t, audit.ActorUser, history[1].ActorType)
+	require.NotNil(t, history[1].ActorID)
+	assert.Equal(t, user.ID, *history[1].ActorID)
+
+	history, err = bookingService.GetBookingHistory(userCtx, user.ID, expired.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, models.BookingStatusExpired, history[1].ToStatus)
+	assert.Equal(t, models.BookingReasonExpiredByWorker, history[1].Reason)
+	assert.Equal(t, audit.ActorSystem, history[1].ActorType)
+
+	// a lost transition leaves no entry
+	assert.Error(t, bookingService.ConfirmPayment(userCtx, user.ID, expired.ID))
+	history, err = bookingService.GetBookingHistory(userCtx, user.ID, expired.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	// served over HTTP
+	app := fiber.New()
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, handler.NewBookingHandler(bookingService),
+		&handler.WebhookHandler{}, &handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{}, nil, nil, routes.RateLimits{}).Setup(app)
+
+	get := func(id int) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/bookings/%d/history", id), nil)
+		req.Header.Set("X-User-ID", strconv.Itoa(user.ID))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := get(cancelled.ID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var body struct {
+		Data []*models.BookingStatusChange `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Data, 2)
+	assert.Equal(t, models.BookingStatusCancelled, body.Data[1].ToStatus)
+
+	assert.Equal(t, http.StatusNotFound, get(cancelled.ID+1000).StatusCode)
+}
@@ -6,6 +6,7 @@ import (
 	"event-booking-be/internal/repository"
 	"event-booking-be/internal/service"
 	"fmt"
+	"log/slog"
 	"sync"
 	"testing"
 	"time"
@@ -17,13 +18,24 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+// testModels is every table the API owns; tests create them with AutoMigrate
+// and migrations_test checks the SQL migrations cover each of their columns.
+var testModels = []interface{}{
+	&models.Event{}, &models.User{}, &models.Booking{}, &models.OutboxMessage{},
+	&models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.Reminder{},
+	&models.Job{}, &models.AuditLog{}, &models.BookingStatusChange{}, &models.TicketType{},
+}
+
+// testLogger discards service logs to keep test output readable.
+var testLogger = slog.New(slog.DiscardHandler)
+
 func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&models.Event{}, &models.User{}, &models.Booking{})
+	err = db.AutoMigrate(testModels...)
 	require.NoError(t, err)
 
 	return db
@@ -36,7 +48,9 @@ func TestCreateBooking_Success(t *testing.T) {
 	eventRepo := repository.NewEventRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
 	userRepo := repository.NewUserRepository(db)
-	bookingService := service.NewBookingService(bookingRepo, eventRepo, db, 15)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
 
 	// setup test data
 	event := &models.Event{
@@ -69,7 +83,9 @@ func TestCreateBooking_InsufficientTickets(t *testing.T) {
 	eventRepo := repository.NewEventRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
 	userRepo := repository.NewUserRepository(db)
-	bookingService := service.NewBookingService(bookingRepo, eventRepo, db, 15)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
 
 	event := &models.Event{
 		Name:         "Small Event",
@@ -100,7 +116,9 @@ func TestCancelBooking_Success(t *testing.T) {
 	eventRepo := repository.NewEventRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
 	userRepo := repository.NewUserRepository(db)
-	bookingService := service.NewBookingService(bookingRepo, eventRepo, db, 15)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
 
 	event := &models.Event{
 		Name:         "Event",
@@ -119,7 +137,7 @@ func TestCancelBooking_Success(t *testing.T) {
 	}
 	booking, _ := bookingService.CreateBooking(ctx, user.ID, req)
 
-	err := bookingService.CancelBooking(ctx, booking.ID)
+	err := bookingService.CancelBooking(ctx, user.ID, booking.ID)
 
 	assert.NoError(t, err)
 	
@@ -134,7 +152,9 @@ func TestConcurrentBookings(t *testing.T) {
 	eventRepo := repository.NewEventRepository(db)
 	bookingRepo := repository.NewBookingRepository(db)
 	userRepo := repository.NewUserRepository(db)
-	bookingService := service.NewBookingService(bookingRepo, eventRepo, db, 15)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
 
 	event := &models.Event{
 		Name:         "Limited Event",
@@ -191,7 +211,8 @@ func TestCreateEvent(t *testing.T) {
 	ctx := context.Background()
 
 	eventRepo := repository.NewEventRepository(db)
-	eventService := service.NewEventService(eventRepo)
+	outboxRepo := repository.NewOutboxRepository(db)
+	eventService := service.NewEventService(eventRepo, repository.NewUserRepository(db), outboxRepo, repository.NewAuditRepository(db), db)
 
 	req := &models.CreateEventRequest{
 		Name:         "Music Festival",
@@ -201,10 +222,136 @@ func TestCreateEvent(t *testing.T) {
 		TicketPrice:  150.0,
 	}
 
-	event, err := eventService.CreateEvent(ctx, req)
+	event, err := eventService.CreateEvent(ctx, 1, req)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, event)
 	assert.Equal(t, "Music Festival", event.Name)
 	assert.Equal(t, 5000, event.TotalTickets)
 }
+
+func TestConfirmAndCancelRace_ExactlyOneWins(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	// one connection: the racing goroutines interleave between statements and
+	// transactions instead of tripping over sqlite's table locks
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.SetMaxOpenConns(0)
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
+
+	event := &models.Event{
+		Name:         "Race Event",
+		DateTime:     time.Now().Add(48 * time.Hour),
+		TotalTickets: 40,
+		TicketPrice:  20.0,
+	}
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	user := &models.User{Name: "Racer", Email: "racer@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	numBookings := 20
+	bookings := make([]*models.Booking, numBookings)
+	for i := range bookings {
+		booking, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+		require.NoError(t, err)
+		bookings[i] = booking
+	}
+
+	confirmErrs := make([]error, numBookings)
+	cancelErrs := make([]error, numBookings)
+
+	var wg sync.WaitGroup
+	for i, booking := range bookings {
+		wg.Add(2)
+		go func(i, id int) {
+			defer wg.Done()
+			confirmErrs[i] = bookingService.ConfirmPayment(ctx, user.ID, id)
+		}(i, booking.ID)
+		go func(i, id int) {
+			defer wg.Done()
+			cancelErrs[i] = bookingService.CancelBooking(ctx, user.ID, id)
+		}(i, booking.ID)
+	}
+	wg.Wait()
+
+	confirmed := 0
+	for i, booking := range bookings {
+		assert.True(t, (confirmErrs[i] == nil) != (cancelErrs[i] == nil),
+			"booking %d: confirm=%v cancel=%v", booking.ID, confirmErrs[i], cancelErrs[i])
+
+		stored, err := bookingRepo.GetByID(ctx, booking.ID)
+		require.NoError(t, err)
+		if confirmErrs[i] == nil {
+			confirmed++
+			assert.Equal(t, models.BookingStatusConfirmed, stored.Status)
+		} else {
+			assert.Equal(t, models.BookingStatusCancelled, stored.Status)
+		}
+	}
+
+	// only the confirmed bookings still hold tickets
+	stored, err := eventRepo.GetByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 40-2*confirmed, stored.TotalTickets)
+}
+
+func TestTransitionStatus_ConfirmAndExpireRace(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.SetMaxOpenConns(0)
+
+	bookingRepo := repository.NewBookingRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	userRepo := repository.NewUserRepository(db)
+
+	event := &models.Event{Name: "CAS Event", DateTime: time.Now().Add(time.Hour), TotalTickets: 10, TicketPrice: 5}
+	require.NoError(t, eventRepo.Create(ctx, event))
+	user := &models.User{Name: "Cas", Email: "cas@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	for i := 0; i < 50; i++ {
+		booking := &models.Booking{
+			UserID:      user.ID,
+			EventID:     event.ID,
+			TicketCount: 1,
+			TotalPrice:  5,
+			Status:      models.BookingStatusPending,
+			ExpiresAt:   time.Now(),
+		}
+		require.NoError(t, bookingRepo.Create(ctx, booking))
+
+		var confirmErr, expireErr error
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			confirmErr = bookingRepo.TransitionStatus(ctx, booking.ID, models.BookingStatusPending, models.BookingStatusConfirmed, "test")
+		}()
+		go func() {
+			defer wg.Done()
+			expireErr = bookingRepo.TransitionStatus(ctx, booking.ID, models.BookingStatusPending, models.BookingStatusExpired, "test")
+		}()
+		wg.Wait()
+
+		require.True(t, (confirmErr == nil) != (expireErr == nil), "confirm=%v expire=%v", confirmErr, expireErr)
+		if confirmErr != nil {
+			assert.Contains(t, confirmErr.Error(), "already expired")
+		} else {
+			assert.Contains(t, expireErr.Error(), "already confirmed")
+		}
+	}
+}
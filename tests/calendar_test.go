@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendarFeeds(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	eventService := service.NewEventService(eventRepo, userRepo, repository.NewOutboxRepository(db), auditRepo, db)
+	calendarService := service.NewCalendarService(eventRepo, bookingRepo, userRepo, auditRepo, db, "test.local")
+
+	organizer := &models.User{Name: "Cal", Email: "cal-organizer@test.com"}
+	require.NoError(t, userRepo.Create(ctx, organizer))
+	fan := &models.User{Name: "Fay", Email: "fay-calendar@test.com"}
+	require.NoError(t, userRepo.Create(ctx, fan))
+
+	start := time.Now().Add(14 * 24 * time.Hour).Truncate(time.Second).In(time.FixedZone("CEST", 2*60*60))
+	event, err := eventService.CreateEvent(ctx, organizer.ID, &models.CreateEventRequest{
+		Name: "Jazz, Live; Late", Description: strings.Repeat("An evening of standards. ", 8), DateTime: start, TotalTickets: 50, TicketPrice: 20,
+	})
+	require.NoError(t, err)
+	for _, tickets := range []int{2, 1} {
+		require.NoError(t, bookingRepo.Create(ctx, &models.Booking{UserID: fan.ID, EventID: event.ID, TicketCount: tickets,
+			TotalPrice: 20, Status: models.BookingStatusConfirmed, ExpiresAt: time.Now()}))
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, &handler.BookingHandler{}, &handler.WebhookHandler{},
+		&handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, handler.NewCalendarHandler(calendarService),
+		nil, nil, routes.RateLimits{}).Setup(app)
+
+	do := func(method, path string, userID int) (int, string) {
+		req := httptest.NewRequest(method, path, nil)
+		if userID != 0 {
+			req.Header.Set("X-User-ID", strconv.Itoa(userID))
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode == http.StatusOK {
+			assert.Equal(t, "text/calendar; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+		}
+		return resp.StatusCode, string(body)
+	}
+	uid := fmt.Sprintf("UID:event-%d@test.local\r\n", event.ID)
+	dtstart := "DTSTART:" + start.UTC().Format("20060102T150405Z") + "\r\n"
+
+	status, ics := do(http.MethodGet, fmt.Sprintf("/api/v1/events/%d/calendar.ics", event.ID), 0)
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Contains(t, ics, uid)
+	assert.Contains(t, ics, dtstart)
+	assert.Contains(t, ics, "SEQUENCE:0\r\n")
+	assert.Contains(t, ics, `SUMMARY:Jazz\, Live\; Late`)
+	for _, line := range strings.Split(ics, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "unfolded line %q", line)
+	}
+
+	// moving the event keeps the UID and bumps the sequence
+	later := start.Add(time.Hour)
+	_, err = eventService.UpdateEvent(ctx, organizer.ID, event.ID, &models.UpdateEventRequest{DateTime: &later})
+	require.NoError(t, err)
+	_, ics = do(http.MethodGet, fmt.Sprintf("/api/v1/events/%d/calendar.ics", event.ID), 0)
+	assert.Contains(t, ics, uid)
+	assert.Contains(t, ics, "SEQUENCE:1\r\n")
+	assert.Contains(t, ics, "DTSTART:"+later.UTC().Format("20060102T150405Z")+"\r\n")
+
+	// the private feed needs a token, issued to a signed-in user
+	status, _ = do(http.MethodPost, "/api/v1/users/calendar-token", 0)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	issue := func() *models.CalendarToken {
+		status, body := do(http.MethodPost, "/api/v1/users/calendar-token", fan.ID)
+		require.Equal(t, http.StatusCreated, status, body)
+		var envelope struct {
+			Data *models.CalendarToken `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &envelope))
+		return envelope.Data
+	}
+	first := issue()
+	assert.Equal(t, "/api/v1/calendar/"+first.Token+"/bookings.ics", first.FeedPath)
+
+	status, ics = do(http.MethodGet, first.FeedPath, 0)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, 1, strings.Count(ics, "BEGIN:VEVENT"), "one entry per event")
+	assert.Contains(t, ics, uid)
+	assert.Contains(t, ics, "2 ticket(s)")
+	assert.Contains(t, ics, "STATUS:CONFIRMED\r\n")
+
+	// deleting the event turns it into a cancellation in both feeds
+	require.NoError(t, eventService.DeleteEvent(ctx, organizer.ID, event.ID))
+	for _, path := range []string{first.FeedPath, "/api/v1/events/calendar.ics"} {
+		status, ics = do(http.MethodGet, path, 0)
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, ics, uid, path)
+		assert.Contains(t, ics, "STATUS:CANCELLED\r\n", path)
+		assert.Contains(t, ics, "SEQUENCE:2\r\n", path)
+	}
+	status, _ = do(http.MethodGet, fmt.Sprintf("/api/v1/events/%d/calendar.ics", event.ID), 0)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// issuing a new token revokes the old one
+	second := issue()
+	status, _ = do(http.MethodGet, first.FeedPath, 0)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = do(http.MethodGet, second.FeedPath, 0)
+	assert.Equal(t, http.StatusOK, status)
+}
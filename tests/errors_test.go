@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/middleware"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/utils"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors_DomainErrorsMapToStatusAndCode(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	eventRepo := repository.NewEventRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	bookingService := service.NewBookingService(repository.NewBookingRepository(db), eventRepo, userRepo,
+		repository.NewOutboxRepository(db), repository.NewJobRepository(db), repository.NewAuditRepository(db), db, 15, testLogger)
+	eventService := service.NewEventService(eventRepo, userRepo, repository.NewOutboxRepository(db), repository.NewAuditRepository(db), db)
+
+	event := &models.Event{Name: "Errors Event", DateTime: time.Now().Add(time.Hour), TotalTickets: 3, TicketPrice: 1}
+	require.NoError(t, eventRepo.Create(ctx, event))
+	user := &models.User{Name: "Eve", Email: "eve-errors@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	booking, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+	require.NoError(t, bookingService.CancelBooking(ctx, user.ID, booking.ID))
+
+	// sentinels survive the services' wrapping
+	_, err = bookingService.GetBooking(ctx, user.ID, 999999)
+	assert.True(t, errors.Is(err, apperrors.ErrBookingNotFound), "got %v", err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	bookingHandler := handler.NewBookingHandler(bookingService)
+	eventHandler := handler.NewEventHandler(eventService)
+	app.Get("/events/:id/statistics", eventHandler.GetEventStatistics)
+	app.Post("/bookings", middleware.AuthMiddleware(), bookingHandler.CreateBooking)
+	app.Get("/bookings/:id", middleware.AuthMiddleware(), bookingHandler.GetBooking)
+	app.Post("/bookings/:id/confirm", middleware.AuthMiddleware(), bookingHandler.ConfirmPayment)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"missing event statistics", "GET", "/events/999999/statistics", "", 404, utils.EVENT_NOT_FOUND},
+		{"missing booking", "GET", "/bookings/999999", "", 404, utils.BOOKING_NOT_FOUND},
+		{"confirm cancelled booking", "POST", fmt.Sprintf("/bookings/%d/confirm", booking.ID), "", 409, utils.BOOKING_ALREADY_CANCELLED},
+		{"too many tickets", "POST", "/bookings", fmt.Sprintf(`{"event_id":%d,"ticket_count":10}`, event.ID), 409, utils.BOOKING_NOT_ENOUGH_TICKETS},
+		{"unknown route", "GET", "/nowhere", "", 404, "NOT_FOUND"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", fmt.Sprint(user.ID))
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+
+			var body handler.Response
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.False(t, body.Success)
+			assert.Equal(t, tc.code, body.Code)
+			assert.NotEmpty(t, body.Error)
+		})
+	}
+}
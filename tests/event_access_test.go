@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"context"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventAccess_OnlyOrganizerOrAdminCanChangeEvent(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	eventService := service.NewEventService(eventRepo, userRepo, repository.NewOutboxRepository(db), repository.NewAuditRepository(db), db)
+
+	newUser := func(email string) *models.User {
+		user := &models.User{Name: email, Email: email}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+	organizer := newUser("organizer-events@test.com")
+	stranger := newUser("stranger-events@test.com")
+	admin := newUser("admin-events@test.com")
+	require.NoError(t, userRepo.UpdateRole(ctx, admin.ID, models.UserRoleAdmin))
+
+	event, err := eventService.CreateEvent(ctx, organizer.ID, &models.CreateEventRequest{
+		Name: "Owned Event", DateTime: time.Now().Add(24 * time.Hour), TotalTickets: 10, TicketPrice: 1,
+	})
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, handler.NewEventHandler(eventService), &handler.BookingHandler{},
+		&handler.WebhookHandler{}, &handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{}, nil, nil, routes.RateLimits{}).Setup(app)
+
+	call := func(method string, userID int, body string) int {
+		req := httptest.NewRequest(method, fmt.Sprintf("/api/v1/events/%d", event.ID), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", strconv.Itoa(userID))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// other users are told the event doesn't exist, and nothing changes
+	assert.Equal(t, http.StatusNotFound, call(http.MethodPut, stranger.ID, `{"name":"Hijacked"}`))
+	assert.Equal(t, http.StatusNotFound, call(http.MethodDelete, stranger.ID, ""))
+	current, err := eventRepo.GetByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Owned Event", current.Name)
+
+	assert.Equal(t, http.StatusOK, call(http.MethodPut, organizer.ID, `{"name":"Renamed"}`))
+	assert.Equal(t, http.StatusOK, call(http.MethodPut, admin.ID, `{"name":"Renamed Again"}`))
+	assert.Equal(t, http.StatusOK, call(http.MethodDelete, organizer.ID, ""))
+}
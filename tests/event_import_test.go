@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/importer"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportEvents(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	eventService := service.NewEventService(eventRepo, userRepo, repository.NewOutboxRepository(db), repository.NewAuditRepository(db), db)
+
+	organizer := &models.User{Name: "Iris", Email: "iris-import@test.com"}
+	require.NoError(t, userRepo.Create(ctx, organizer))
+
+	when := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+	file := func(lines ...string) []*models.EventImportRow {
+		rows, err := importer.ReadEvents(strings.NewReader(strings.Join(lines, "\n")), importer.FormatCSV)
+		require.NoError(t, err)
+		return rows
+	}
+	header := "external_ref,name,date_time,total_tickets,ticket_price"
+	importFile := func(opts models.EventImportOptions, lines ...string) *models.EventImportResult {
+		result, err := eventService.ImportEvents(ctx, organizer.ID, file(append([]string{header}, lines...)...), opts)
+		require.NoError(t, err)
+		return result
+	}
+	countEvents := func() int64 {
+		var n int64
+		require.NoError(t, db.Model(&models.Event{}).Where("organizer_id = ?", organizer.ID).Count(&n).Error)
+		return n
+	}
+
+	t.Run("atomic import fails as a whole", func(t *testing.T) {
+		result := importFile(models.EventImportOptions{},
+			"gala-1,Gala,"+when+",100,25",
+			"gala-2,,"+when+",lots,25",
+		)
+		assert.False(t, result.Applied)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, models.ImportActionSkipped, result.Rows[0].Action)
+		assert.Equal(t, models.ImportActionFailed, result.Rows[1].Action)
+		require.Len(t, result.Rows[1].Errors, 2)
+		assert.Equal(t, "name", result.Rows[1].Errors[1].Field)
+		assert.Equal(t, "total_tickets", result.Rows[1].Errors[0].Field)
+		assert.Equal(t, int64(0), countEvents())
+	})
+
+	t.Run("dry run reports without writing", func(t *testing.T) {
+		result := importFile(models.EventImportOptions{DryRun: true}, "gala-1,Gala,"+when+",100,25")
+		assert.False(t, result.Applied)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, int64(0), countEvents())
+	})
+
+	t.Run("best effort applies the good rows", func(t *testing.T) {
+		result := importFile(models.EventImportOptions{Mode: models.ImportModeBestEffort},
+			"gala-1,Gala,"+when+",100,25",
+			"gala-2,Gala Two,2001-01-01T00:00:00Z,100,25",
+			"gala-1,Gala Again,"+when+",100,25",
+		)
+		assert.True(t, result.Applied)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 2, result.Failed)
+		assert.Equal(t, "NOT_IN_FUTURE", result.Rows[1].Errors[0].Code)
+		assert.Equal(t, "DUPLICATE", result.Rows[2].Errors[0].Code)
+		assert.Equal(t, int64(1), countEvents())
+	})
+
+	t.Run("re-imports update by external reference", func(t *testing.T) {
+		result := importFile(models.EventImportOptions{}, "gala-1,Gala,"+when+",100,25")
+		assert.Equal(t, 1, result.Unchanged)
+
+		event, err := eventRepo.GetByExternalRef(ctx, organizer.ID, "gala-1")
+		require.NoError(t, err)
+		// 30 tickets are booked
+		require.NoError(t, db.Model(event).Update("total_tickets", 70).Error)
+
+		result = importFile(models.EventImportOptions{}, "gala-1,Gala,"+when+",20,25")
+		assert.Equal(t, "BELOW_BOOKED", result.Rows[0].Errors[0].Code)
+
+		result = importFile(models.EventImportOptions{}, "gala-1,Gala,"+when+",150,30")
+		assert.True(t, result.Applied)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, event.ID, result.Rows[0].EventID)
+
+		event, err = eventRepo.GetByID(ctx, event.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 150, event.Capacity)
+		assert.Equal(t, 120, event.TotalTickets)
+		assert.Equal(t, 30.0, event.TicketPrice)
+		assert.Equal(t, int64(1), countEvents())
+	})
+
+	t.Run("over http", func(t *testing.T) {
+		app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+		routes.NewRouter(&handler.UserHandler{}, handler.NewEventHandler(eventService), &handler.BookingHandler{}, &handler.WebhookHandler{},
+			&handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{}, nil, nil, routes.RateLimits{}).Setup(app)
+
+		post := func(contentType, query, body string) (int, *models.EventImportResult) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/events/import?"+query, strings.NewReader(body))
+			req.Header.Set(fiber.HeaderContentType, contentType)
+			req.Header.Set("X-User-ID", strconv.Itoa(organizer.ID))
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			var envelope struct {
+				Data *models.EventImportResult `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&envelope))
+			return resp.StatusCode, envelope.Data
+		}
+
+		status, result := post("application/json", "mode=best_effort", fmt.Sprintf(
+			`[{"external_ref": "talk-1", "name": "Talk", "date_time": %q, "total_tickets": 40},
+			  {"external_ref": "talk-2", "name": "Talk Two", "date_time": %q, "total_tickets": "forty"}]`, when, when))
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, "INVALID_TYPE", result.Rows[1].Errors[0].Code)
+
+		status, _ = post("text/csv", "", "external_ref,venue\ntalk-1,Hall A\n")
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _ = post("text/csv", "mode=all_or_nothing", header+"\n")
+		assert.Equal(t, http.StatusUnprocessableEntity, status)
+	})
+}
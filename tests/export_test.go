@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAttendees(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, repository.NewOutboxRepository(db),
+		repository.NewJobRepository(db), repository.NewAuditRepository(db), db, 15, testLogger)
+
+	newUser := func(name, email string) *models.User {
+		user := &models.User{Name: name, Email: email}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+	organizer := newUser("Olive", "olive-export@test.com")
+	stranger := newUser("Sam", "sam-export@test.com")
+	alice := newUser("Alice", "alice-export@test.com")
+	mallory := newUser("=HYPERLINK(\"http://evil\")", "mallory-export@test.com")
+
+	event := &models.Event{Name: "Export Fest", DateTime: time.Now().Add(24 * time.Hour), TotalTickets: 50, TicketPrice: 5, OrganizerID: &organizer.ID}
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	created := time.Now().Add(-time.Hour)
+	checkedIn := time.Date(2026, 5, 1, 18, 30, 0, 0, time.UTC)
+	for i, b := range []*models.Booking{
+		{UserID: alice.ID, TicketCount: 2, Status: models.BookingStatusConfirmed, TicketType: "VIP", CheckedInAt: &checkedIn},
+		{UserID: mallory.ID, TicketCount: 1, Status: models.BookingStatusConfirmed},
+		{UserID: stranger.ID, TicketCount: 4, Status: models.BookingStatusCancelled},
+	} {
+		b.EventID, b.TotalPrice = event.ID, float64(b.TicketCount)*event.TicketPrice
+		b.CreatedAt = created.Add(time.Duration(i) * time.Minute)
+		b.ExpiresAt = b.CreatedAt.Add(15 * time.Minute)
+		require.NoError(t, bookingRepo.Create(ctx, b))
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, handler.NewBookingHandler(bookingService),
+		&handler.WebhookHandler{}, &handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{}, nil, nil, routes.RateLimits{}).Setup(app)
+
+	get := func(userID int, query string) (*http.Response, []byte) {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/organizer/events/%d/attendees?%s", event.ID, query), nil)
+		req.Header.Set("X-User-ID", strconv.Itoa(userID))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, body
+	}
+
+	t.Run("csv with selected columns", func(t *testing.T) {
+		resp, body := get(organizer.ID, "columns=name,ticket_count,email")
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+		assert.Contains(t, resp.Header.Get(fiber.HeaderContentDisposition), fmt.Sprintf(`filename="event-%d-attendees.csv"`, event.ID))
+
+		records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+		require.NoError(t, err)
+		// only confirmed bookings, oldest first, with formulas neutralized
+		assert.Equal(t, [][]string{
+			{"Name", "Tickets", "Email"},
+			{"Alice", "2", "alice-export@test.com"},
+			{"'=HYPERLINK(\"http://evil\")", "1", "mallory-export@test.com"},
+		}, records)
+	})
+
+	t.Run("ticket type and check-in columns", func(t *testing.T) {
+		resp, body := get(organizer.ID, "columns=email,ticket_type,check_in_status,checked_in_at")
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"Email", "Ticket Type", "Check-in Status", "Checked In At"},
+			{"alice-export@test.com", "VIP", "checked_in", "2026-05-01T18:30:00Z"},
+			{"mallory-export@test.com", "", "not_checked_in", ""},
+		}, records)
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		resp, body := get(organizer.ID, "format=xlsx")
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), "spreadsheetml")
+
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+		var sheet string
+		for _, f := range archive.File {
+			if f.Name == "xl/worksheets/sheet1.xml" {
+				r, err := f.Open()
+				require.NoError(t, err)
+				data, err := io.ReadAll(r)
+				require.NoError(t, err)
+				sheet = string(data)
+			}
+		}
+		assert.Equal(t, 3, strings.Count(sheet, "<row>"))
+		assert.Contains(t, sheet, "Booked At")
+		assert.Contains(t, sheet, "<c><v>2</v></c>")
+		assert.Contains(t, sheet, "=HYPERLINK(&#34;http://evil&#34;)")
+	})
+
+	t.Run("rejects bad requests", func(t *testing.T) {
+		resp, _ := get(organizer.ID, "columns=name,password")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp, _ = get(organizer.ID, "format=pdf")
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		resp, _ = get(stranger.ID, "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/jobqueue"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobQueue_BookingExpiresOnceAcrossWorkers(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	// zero timeout: the booking expires as soon as it's created
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 0, testLogger)
+
+	event := &models.Event{
+		Name:         "Expiry Event",
+		DateTime:     time.Now().Add(24 * time.Hour),
+		TotalTickets: 5,
+		TicketPrice:  10.0,
+	}
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	user := &models.User{Name: "Jo", Email: "jo-jobs@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	booking, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+	require.NoError(t, err)
+
+	var runs int32
+	handler := func(ctx context.Context, job *models.Job) error {
+		var payload models.ExpireBookingPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return err
+		}
+		if payload.BookingID == booking.ID {
+			atomic.AddInt32(&runs, 1)
+		}
+		return bookingService.ExpireBooking(ctx, payload.BookingID)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		worker := jobqueue.NewWorker(jobRepo, jobqueue.Config{})
+		worker.Handle(models.JobTypeExpireBooking, handler)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = worker.ProcessDue(ctx)
+		}()
+	}
+	wg.Wait()
+
+	worker := jobqueue.NewWorker(jobRepo, jobqueue.Config{})
+	worker.Handle(models.JobTypeExpireBooking, handler)
+	_, err = worker.ProcessDue(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+
+	expired, err := bookingRepo.GetByID(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusExpired, expired.Status)
+
+	restored, err := eventRepo.GetByID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, restored.TotalTickets)
+}
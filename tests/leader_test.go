@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"context"
+	"event-booking-be/internal/leader"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderElection_SingleLeaderAndFailover(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	ctx := context.Background()
+	cfg := leader.Config{Key: "test:leader", TTL: 10 * time.Second}
+
+	var running int32
+	job := func(ctx context.Context) {
+		atomic.AddInt32(&running, 1)
+		<-ctx.Done()
+		atomic.AddInt32(&running, -1)
+	}
+
+	first := leader.NewElector(client, cfg)
+	second := leader.NewElector(client, cfg)
+	first.Register("job", job)
+	second.Register("job", job)
+
+	assert.True(t, first.Step(ctx))
+	assert.False(t, second.Step(ctx))
+	assert.True(t, first.Step(ctx), "leader should renew its own lease")
+
+	current, err := second.Leader(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID(), current)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&running) == 1 }, time.Second, 10*time.Millisecond)
+
+	// the leader stops renewing, as if it died; its lease lapses
+	mr.FastForward(11 * time.Second)
+	assert.True(t, second.Step(ctx))
+	assert.False(t, first.Step(ctx), "old leader must not renew a lease it lost")
+	assert.False(t, first.IsLeader())
+
+	current, err = first.Leader(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID(), current)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&running) == 1 }, time.Second, 10*time.Millisecond)
+
+	// a clean shutdown hands the lease over without waiting for the TTL
+	second.Resign()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&running))
+	assert.True(t, first.Step(ctx))
+
+	first.Resign()
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"event-booking-be/internal/logging"
+	"event-booking-be/internal/middleware"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogging_RequestIDAndContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Use(middleware.RequestLogger(logger))
+	app.Post("/bookings/:id/confirm", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		ctx := logging.With(c.UserContext(), "booking_id", 42, "event_id", 7)
+		logger.InfoContext(ctx, "Booking confirmed")
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("POST", "/bookings/42/confirm", nil)
+	req.Header.Set("X-Request-ID", "req-abc-123")
+	req.Header.Set("X-User-ID", "9")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "req-abc-123", resp.Header.Get("X-Request-ID"))
+
+	var lines []map[string]interface{}
+	var raw []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+		raw = append(raw, scanner.Text())
+	}
+	require.Len(t, lines, 2)
+
+	service, access := lines[0], lines[1]
+	assert.Equal(t, "Booking confirmed", service["msg"])
+	assert.Equal(t, "req-abc-123", service["request_id"])
+	assert.EqualValues(t, 9, service["user_id"])
+	assert.EqualValues(t, 42, service["booking_id"])
+	assert.EqualValues(t, 7, service["event_id"])
+
+	assert.Equal(t, "Request handled", access["msg"])
+	assert.Equal(t, "req-abc-123", access["request_id"])
+	assert.Equal(t, "/bookings/:id/confirm", access["route"])
+	assert.EqualValues(t, 204, access["status"])
+	assert.EqualValues(t, 9, access["user_id"])
+	assert.Equal(t, 1, strings.Count(raw[1], `"user_id"`), "user_id is logged once")
+
+	// a missing or unusable ID is replaced with a generated one
+	req = httptest.NewRequest("POST", "/bookings/42/confirm", nil)
+	req.Header.Set("X-Request-ID", "has spaces\tand tabs")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Len(t, resp.Header.Get("X-Request-ID"), 36)
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"event-booking-be/internal/metrics"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrapeMetrics(t *testing.T, app *fiber.App) string {
+	resp, err := app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics_HTTPAndBookingCounters(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	app := fiber.New()
+	app.Use(metrics.Middleware())
+	app.Get("/metrics", metrics.Handler())
+	app.Get("/api/v1/things/:id", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	for _, id := range []string{"1", "2", "3"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/things/"+id, nil))
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	}
+	_, err := app.Test(httptest.NewRequest("GET", "/nowhere", nil))
+	require.NoError(t, err)
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo,
+		repository.NewOutboxRepository(db), repository.NewJobRepository(db), repository.NewAuditRepository(db), db, 15, testLogger)
+
+	event := &models.Event{Name: "Metrics Event", DateTime: time.Now().Add(time.Hour), TotalTickets: 10, TicketPrice: 1}
+	require.NoError(t, eventRepo.Create(ctx, event))
+	user := &models.User{Name: "Mo", Email: "mo-metrics@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	booking, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 4})
+	require.NoError(t, err)
+	require.NoError(t, bookingService.ConfirmPayment(ctx, user.ID, booking.ID))
+
+	body := scrapeMetrics(t, app)
+
+	// one series for the route template, not one per ID
+	assert.Contains(t, body, `event_booking_http_request_duration_seconds_count{method="GET",route="/api/v1/things/:id",status="200"} 3`)
+	assert.Contains(t, body, `route="unmatched",status="404"`)
+
+	eventID := fmt.Sprint(event.ID)
+	assert.Contains(t, body, fmt.Sprintf(`event_booking_bookings_total{action="created",event_id="%s"} 1`, eventID))
+	assert.Contains(t, body, fmt.Sprintf(`event_booking_bookings_total{action="confirmed",event_id="%s"} 1`, eventID))
+	assert.Contains(t, body, fmt.Sprintf(`event_booking_tickets_sold_total{event_id="%s"} 4`, eventID))
+	assert.Contains(t, body, "event_booking_event_lock_wait_seconds_count")
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"event-booking-be/internal/migrate"
+	"event-booking-be/migrations"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+func TestMigrations_AreSequentialAndReversible(t *testing.T) {
+	all, err := migrate.Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	for i, m := range all {
+		assert.Equal(t, int64(i+1), m.Version, "migration versions must have no gaps")
+		assert.NotEmpty(t, strings.TrimSpace(m.Up), "%06d_%s has an empty up file", m.Version, m.Name)
+		assert.NotEmpty(t, strings.TrimSpace(m.Down), "%06d_%s has an empty down file", m.Version, m.Name)
+	}
+}
+
+// Tests build their schema with AutoMigrate, so this is what keeps the SQL
+// migrations from drifting behind the models.
+func TestMigrations_CoverEveryModelColumn(t *testing.T) {
+	all, err := migrate.Load(migrations.FS)
+	require.NoError(t, err)
+
+	var up strings.Builder
+	for _, m := range all {
+		up.WriteString(m.Up)
+		up.WriteString("\n")
+	}
+	sql := up.String()
+
+	for _, model := range testModels {
+		s, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+		require.NoError(t, err)
+
+		create := regexp.MustCompile(fmt.Sprintf(`(?s)CREATE TABLE IF NOT EXISTS %s \((.*?)\n\);`, s.Table)).FindStringSubmatch(sql)
+		if !assert.NotNil(t, create, "no CREATE TABLE for %s", s.Table) {
+			continue
+		}
+
+		for _, column := range s.DBNames {
+			inCreate := regexp.MustCompile(`(?m)^\s+` + column + `\s`).MatchString(create[1])
+			added := strings.Contains(sql, fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s ", s.Table, column))
+			assert.True(t, inCreate || added, "column %s.%s is not in any migration", s.Table, column)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/notification"
+	"event-booking-be/internal/outbox"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifications_BookingLifecycleEmails(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
+
+	renderer, err := notification.NewRenderer("en")
+	require.NoError(t, err)
+	mailer := notification.NewMemoryMailer()
+	notificationService := service.NewNotificationService(bookingRepo, eventRepo, userRepo, jobRepo, renderer, mailer)
+
+	event := &models.Event{
+		Name:         "Mail Event",
+		DateTime:     time.Now().Add(72 * time.Hour),
+		TotalTickets: 10,
+		TicketPrice:  15.0,
+	}
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	english := &models.User{Name: "Emma", Email: "emma-mail@test.com", Locale: "en"}
+	require.NoError(t, userRepo.Create(ctx, english))
+	vietnamese := &models.User{Name: "Hieu", Email: "hieu-mail@test.com", Locale: "vi"}
+	require.NoError(t, userRepo.Create(ctx, vietnamese))
+
+	confirmed, err := bookingService.CreateBooking(ctx, english.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+	require.NoError(t, err)
+	require.NoError(t, bookingService.ConfirmPayment(ctx, english.ID, confirmed.ID))
+
+	pending, err := bookingService.CreateBooking(ctx, vietnamese.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+
+	relay := outbox.NewRelay(outboxRepo, db, []outbox.Sink{
+		outbox.NewDispatchSink("notifications", notificationService.Dispatch),
+	}, outbox.RelayConfig{})
+	require.NoError(t, relay.Drain(ctx))
+
+	// the emails wait in the job queue until a worker sends them
+	var jobs []*models.Job
+	require.NoError(t, db.Where("type = ? AND status = ?", models.JobTypeSendEmail, models.JobStatusPending).Order("id").Find(&jobs).Error)
+	assert.Empty(t, mailer.Messages())
+	for _, job := range jobs {
+		var payload models.SendEmailPayload
+		require.NoError(t, json.Unmarshal([]byte(job.Payload), &payload))
+		if payload.To == english.Email || payload.To == vietnamese.Email {
+			require.NoError(t, notificationService.SendEmail(ctx, &payload))
+		}
+	}
+
+	byRecipient := map[string][]*notification.Message{}
+	for _, msg := range mailer.Messages() {
+		byRecipient[msg.To] = append(byRecipient[msg.To], msg)
+	}
+
+	require.Len(t, byRecipient[english.Email], 2)
+	assert.Equal(t, "Complete your payment for Mail Event", byRecipient[english.Email][0].Subject)
+	assert.Equal(t, "Your tickets for Mail Event", byRecipient[english.Email][1].Subject)
+	assert.Contains(t, byRecipient[english.Email][1].Text, models.BookingReference(confirmed.ID)+"-02")
+	assert.Contains(t, byRecipient[english.Email][1].HTML, "<li><code>"+models.BookingReference(confirmed.ID)+"-01</code></li>")
+
+	require.Len(t, byRecipient[vietnamese.Email], 1)
+	assert.Equal(t, "Hoàn tất thanh toán cho Mail Event", byRecipient[vietnamese.Email][0].Subject)
+	assert.Contains(t, byRecipient[vietnamese.Email][0].Text, models.BookingReference(pending.ID))
+}
+
+// fakeSMTPServer accepts one session and speaks just enough SMTP to take a
+// message, which it sends on the returned channel.
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := textproto.NewConn(conn)
+		r.PrintfLine("220 fake ESMTP")
+		for {
+			line, err := r.ReadLine()
+			if err != nil {
+				return
+			}
+			switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				r.PrintfLine("250 OK")
+			case "DATA":
+				r.PrintfLine("354 go ahead")
+				data, err := r.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				received <- string(data)
+				r.PrintfLine("250 queued")
+			case "QUIT":
+				r.PrintfLine("221 bye")
+				return
+			default:
+				r.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+	return ln.Addr().String(), received
+}
+
+func smtpMailerFor(t *testing.T, addr string, timeout time.Duration) *notification.SMTPMailer {
+	host, portText, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portText)
+	require.NoError(t, err)
+	return notification.NewSMTPMailer(host, port, "", "", "noreply@test.local", timeout)
+}
+
+func TestSMTPMailer_SendsThroughServer(t *testing.T) {
+	addr, received := fakeSMTPServer(t)
+	mailer := smtpMailerFor(t, addr, 5*time.Second)
+
+	err := mailer.Send(context.Background(), &notification.Message{To: "ann@test.local", Subject: "Hi", Text: "hello there"})
+	require.NoError(t, err)
+	select {
+	case data := <-received:
+		assert.Contains(t, data, "To: ann@test.local")
+		assert.Contains(t, data, "hello there")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the server got no message")
+	}
+}
+
+func TestSMTPMailer_GivesUpOnStuckServer(t *testing.T) {
+	// accepts connections but never greets
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	msg := &notification.Message{To: "ann@test.local", Subject: "Hi", Text: "hello"}
+
+	start := time.Now()
+	err = smtpMailerFor(t, ln.Addr().String(), 200*time.Millisecond).Send(context.Background(), msg)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// cancelling the job's context stops it too, whatever the timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start = time.Now()
+	err = smtpMailerFor(t, ln.Addr().String(), time.Minute).Send(ctx, msg)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
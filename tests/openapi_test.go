@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"encoding/json"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/openapi"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/utils"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAPI_DescribesEveryRoute fails when a route is registered without
+// a matching operation in the document.
+func TestOpenAPI_DescribesEveryRoute(t *testing.T) {
+	app := fiber.New()
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, &handler.BookingHandler{},
+		&handler.WebhookHandler{}, &handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{}, nil, nil, routes.RateLimits{}).Setup(app)
+
+	doc := openapi.Build()
+	registered := 0
+	for _, route := range app.GetRoutes(true) {
+		// Fiber registers HEAD alongside every GET
+		if route.Method == http.MethodHead {
+			continue
+		}
+		registered++
+		assert.True(t, doc.Has(route.Method, route.Path), "%s %s is not in the OpenAPI document", route.Method, route.Path)
+	}
+	assert.NotZero(t, registered)
+
+	for _, path := range []string{"/health", openapi.SpecPath, openapi.DocsPath} {
+		assert.True(t, doc.Has(http.MethodGet, path), "GET %s is not in the OpenAPI document", path)
+	}
+}
+
+func TestOpenAPI_SchemasFollowModelTags(t *testing.T) {
+	doc := openapi.Build()
+	schemas := doc.Components.Schemas
+
+	create := schemas["CreateBookingRequest"]
+	require.NotNil(t, create)
+	assert.ElementsMatch(t, []string{"event_id", "ticket_count"}, create.Required)
+	require.NotNil(t, create.Properties["ticket_count"].Maximum)
+	assert.Equal(t, 10.0, *create.Properties["ticket_count"].Maximum)
+
+	user := schemas["CreateUserRequest"]
+	require.NotNil(t, user)
+	assert.Equal(t, "email", user.Properties["email"].Format)
+	assert.Equal(t, []string{"en", "vi"}, user.Properties["locale"].Enum)
+
+	// embedded Booking fields are flattened, json:"-" relations are not exposed
+	details := schemas["BookingWithDetails"]
+	require.NotNil(t, details)
+	assert.Contains(t, details.Properties, "ticket_count")
+	assert.Contains(t, details.Properties, "event_name")
+	assert.NotContains(t, details.Properties, "User")
+	assert.NotContains(t, details.Required, "confirmed_at")
+
+	assert.Contains(t, schemas, "Response")
+	assert.Contains(t, schemas, "FieldError")
+	assert.Equal(t, utils.ErrorCodes, schemas["ErrorCode"].Enum)
+}
+
+// TestOpenAPI_ListsEveryErrorCode keeps utils.ErrorCodes, and so the
+// published enum, in step with the constants in utils/error_code.go.
+func TestOpenAPI_ListsEveryErrorCode(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "../internal/utils/error_code.go", nil, 0)
+	require.NoError(t, err)
+
+	var declared []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		decl, ok := n.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST {
+			return true
+		}
+		for _, spec := range decl.Specs {
+			for _, value := range spec.(*ast.ValueSpec).Values {
+				if lit, ok := value.(*ast.BasicLit); ok {
+					code, err := strconv.Unquote(lit.Value)
+					require.NoError(t, err)
+					declared = append(declared, code)
+				}
+			}
+		}
+		return false
+	})
+
+	require.NotEmpty(t, declared)
+	assert.ElementsMatch(t, declared, utils.ErrorCodes)
+}
+
+func TestOpenAPI_ServesDocument(t *testing.T) {
+	app := fiber.New()
+	app.Get(openapi.SpecPath, openapi.Handler())
+	app.Get(openapi.DocsPath, openapi.DocsHandler())
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, openapi.SpecPath, nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+	assert.Contains(t, doc["paths"], "/api/v1/bookings/{id}")
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, openapi.DocsPath, nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), "text/html")
+}
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/outbox"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	mu       sync.Mutex
+	failOnce map[int64]bool
+	received []*models.OutboxMessage
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Publish(ctx context.Context, message *models.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failOnce[message.ID] {
+		delete(s.failOnce, message.ID)
+		return errors.New("sink unavailable")
+	}
+	s.received = append(s.received, message)
+	return nil
+}
+
+func (s *recordingSink) eventTypesFor(bookingID int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var types []string
+	for _, message := range s.received {
+		if message.AggregateType == models.AggregateTypeBooking && message.AggregateID == bookingID {
+			types = append(types, message.EventType)
+		}
+	}
+	return types
+}
+
+func TestOutbox_BookingLifecycleIsRelayedInOrder(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
+
+	event := &models.Event{
+		Name:         "Outbox Event",
+		DateTime:     time.Now().Add(24 * time.Hour),
+		TotalTickets: 10,
+		TicketPrice:  20.0,
+	}
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	user := &models.User{Name: "Olive", Email: "olive@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	booking, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{
+		EventID:     event.ID,
+		TicketCount: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, bookingService.CancelBooking(ctx, user.ID, booking.ID))
+
+	// drain anything left over from other tests sharing the database
+	sink := &recordingSink{failOnce: map[int64]bool{}}
+	relay := outbox.NewRelay(outboxRepo, db, []outbox.Sink{sink}, outbox.RelayConfig{
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+
+	pending, err := outboxRepo.GetPending(ctx, 100)
+	require.NoError(t, err)
+	for _, message := range pending {
+		if message.AggregateID == booking.ID && message.EventType == models.DomainEventBookingCreated {
+			sink.failOnce[message.ID] = true
+		}
+	}
+
+	// first pass: "created" fails, so "cancelled" must wait behind it
+	_, err = relay.ProcessBatch(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sink.eventTypesFor(booking.ID))
+
+	time.Sleep(5 * time.Millisecond)
+	_, err = relay.ProcessBatch(ctx)
+	require.NoError(t, err)
+	_, err = relay.ProcessBatch(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		models.DomainEventBookingCreated,
+		models.DomainEventBookingCancelled,
+	}, sink.eventTypesFor(booking.ID))
+}
+
+func TestOutbox_FailedSinkRollsBackDispatchWrites(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+
+	message := &models.OutboxMessage{AggregateType: "test", AggregateID: 1, EventType: "test.rollback", Payload: "{}"}
+	require.NoError(t, outboxRepo.Create(ctx, message))
+	uniqueKey := fmt.Sprintf("outbox-rollback-%d", message.ID)
+
+	// the first sink writes a row, the second fails once after it
+	writer := outbox.NewDispatchSink("writer", func(ctx context.Context, m *models.OutboxMessage) error {
+		if m.ID != message.ID {
+			return nil
+		}
+		return jobRepo.Create(ctx, &models.Job{Type: "test.rollback", Payload: "{}", UniqueKey: &uniqueKey, RunAt: time.Now().Add(time.Hour)})
+	})
+	failed := false
+	flaky := outbox.NewDispatchSink("flaky", func(ctx context.Context, m *models.OutboxMessage) error {
+		if m.ID == message.ID && !failed {
+			failed = true
+			return errors.New("sink unavailable")
+		}
+		return nil
+	})
+	relay := outbox.NewRelay(outboxRepo, db, []outbox.Sink{writer, flaky}, outbox.RelayConfig{
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+
+	countJobs := func() int64 {
+		var count int64
+		require.NoError(t, db.Model(&models.Job{}).Where("unique_key = ?", uniqueKey).Count(&count).Error)
+		return count
+	}
+	reload := func() *models.OutboxMessage {
+		var m models.OutboxMessage
+		require.NoError(t, db.First(&m, message.ID).Error)
+		return &m
+	}
+
+	require.NoError(t, relay.Drain(ctx))
+	assert.Zero(t, countJobs(), "the failed message's writes roll back")
+	assert.Nil(t, reload().PublishedAt)
+	assert.Equal(t, 1, reload().Attempts)
+
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, relay.Drain(ctx))
+	assert.Equal(t, int64(1), countJobs(), "the retry writes them once")
+	assert.NotNil(t, reload().PublishedAt)
+}
+
+func TestOutbox_BestEffortSinkErrorsDoNotHoldMessagesBack(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	outboxRepo := repository.NewOutboxRepository(db)
+
+	message := &models.OutboxMessage{AggregateType: "test", AggregateID: 2, EventType: "test.best_effort", Payload: "{}"}
+	require.NoError(t, outboxRepo.Create(ctx, message))
+
+	sink := &recordingSink{failOnce: map[int64]bool{}}
+	relay := outbox.NewRelay(outboxRepo, db, []outbox.Sink{
+		outbox.NewBestEffortSink("live", func(ctx context.Context, m *models.OutboxMessage) error {
+			return errors.New("redis unavailable")
+		}, testLogger),
+		sink,
+	}, outbox.RelayConfig{})
+
+	require.NoError(t, relay.Drain(ctx))
+
+	var published models.OutboxMessage
+	require.NoError(t, db.First(&published, message.ID).Error)
+	assert.NotNil(t, published.PublishedAt)
+	assert.Zero(t, published.Attempts)
+}
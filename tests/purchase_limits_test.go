@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func purchaseLimitsSetup(t *testing.T, limits models.Event) (*gorm.DB, service.BookingService, *models.Event) {
+	db := setupTestDB(t)
+	eventRepo := repository.NewEventRepository(db)
+	bookingService := service.NewBookingService(repository.NewBookingRepository(db), eventRepo, repository.NewUserRepository(db),
+		repository.NewOutboxRepository(db), repository.NewJobRepository(db), repository.NewAuditRepository(db), db, 15, testLogger)
+
+	event := &limits
+	event.Name = "Limited Drop"
+	event.DateTime = time.Now().Add(24 * time.Hour)
+	event.TotalTickets = 100
+	event.TicketPrice = 10
+	require.NoError(t, eventRepo.Create(context.Background(), event))
+	return db, bookingService, event
+}
+
+func limitTestUser(t *testing.T, db *gorm.DB, email string) *models.User {
+	user := &models.User{Name: email, Email: email}
+	require.NoError(t, repository.NewUserRepository(db).Create(context.Background(), user))
+	return user
+}
+
+func TestPurchaseLimits_PerOrderAndPerUser(t *testing.T) {
+	db, bookingService, event := purchaseLimitsSetup(t, models.Event{MaxTicketsPerOrder: 3, MaxTicketsPerUser: 4})
+	ctx := context.Background()
+	user := limitTestUser(t, db, "scalper@limits.test")
+
+	_, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 4})
+	assert.True(t, errors.Is(err, apperrors.ErrOrderLimitExceeded), "got %v", err)
+
+	first, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 3})
+	require.NoError(t, err)
+
+	_, err = bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+	assert.True(t, errors.Is(err, apperrors.ErrUserLimitExceeded), "got %v", err)
+	assert.Equal(t, apperrors.KindConflict, apperrors.KindOf(err))
+
+	_, err = bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+
+	// cancelled bookings no longer count
+	require.NoError(t, bookingService.CancelBooking(ctx, user.ID, first.ID))
+	_, err = bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 3})
+	require.NoError(t, err)
+
+	// a limit is lifted by setting it back to 0
+	admin := &models.User{Name: "Admin", Email: "admin@limits.test", Role: models.UserRoleAdmin}
+	require.NoError(t, repository.NewUserRepository(db).Create(ctx, admin))
+	eventService := service.NewEventService(repository.NewEventRepository(db), repository.NewUserRepository(db), repository.NewOutboxRepository(db), repository.NewAuditRepository(db), db)
+	noLimit := 0
+	_, err = eventService.UpdateEvent(ctx, admin.ID, event.ID, &models.UpdateEventRequest{MaxTicketsPerUser: &noLimit})
+	require.NoError(t, err)
+	_, err = bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 3})
+	assert.NoError(t, err)
+}
+
+func TestPurchaseLimits_PerPaymentInstrument(t *testing.T) {
+	db, bookingService, event := purchaseLimitsSetup(t, models.Event{MaxTicketsPerPaymentInstrument: 2})
+	ctx := context.Background()
+	alice := limitTestUser(t, db, "alice@limits.test")
+	bob := limitTestUser(t, db, "bob@other.test")
+
+	_, err := bookingService.CreateBooking(ctx, alice.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	assert.True(t, errors.Is(err, apperrors.ErrInstrumentRequired), "got %v", err)
+
+	_, err = bookingService.CreateBooking(ctx, alice.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2, PaymentInstrument: "card_abc"})
+	require.NoError(t, err)
+
+	// a second account paying with the same card is still over the limit
+	_, err = bookingService.CreateBooking(ctx, bob.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1, PaymentInstrument: "card_abc"})
+	assert.True(t, errors.Is(err, apperrors.ErrInstrumentLimitExceeded), "got %v", err)
+
+	_, err = bookingService.CreateBooking(ctx, bob.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1, PaymentInstrument: "card_xyz"})
+	assert.NoError(t, err)
+}
+
+func TestPurchaseLimits_PerEmailDomain(t *testing.T) {
+	db, bookingService, event := purchaseLimitsSetup(t, models.Event{MaxTicketsPerEmailDomain: 3})
+	ctx := context.Background()
+	farm1 := limitTestUser(t, db, "bot1@farm.test")
+	farm2 := limitTestUser(t, db, "Bot2@FARM.test")
+	other := limitTestUser(t, db, "fan@elsewhere.test")
+
+	_, err := bookingService.CreateBooking(ctx, farm1.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+	require.NoError(t, err)
+
+	_, err = bookingService.CreateBooking(ctx, farm2.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+	assert.True(t, errors.Is(err, apperrors.ErrDomainLimitExceeded), "got %v", err)
+
+	_, err = bookingService.CreateBooking(ctx, other.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+	assert.NoError(t, err)
+
+	// LIKE wildcards in a domain only match themselves
+	wild := limitTestUser(t, db, "x@far_.test")
+	_, err = bookingService.CreateBooking(ctx, wild.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2})
+	assert.NoError(t, err)
+}
+
+func TestPurchaseLimits_ConcurrentOrdersCannotBypassUserLimit(t *testing.T) {
+	db, bookingService, event := purchaseLimitsSetup(t, models.Event{MaxTicketsPerUser: 2})
+	ctx := context.Background()
+	user := limitTestUser(t, db, "burst@limits.test")
+
+	var wg sync.WaitGroup
+	results := make([]error, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, results[i] = bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+		}(i)
+	}
+	wg.Wait()
+
+	held, err := repository.NewBookingRepository(db).SumHeldTicketsByUser(ctx, event.ID, user.ID)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, held, 2, fmt.Sprintf("results: %v", results))
+	assert.Greater(t, held, 0)
+}
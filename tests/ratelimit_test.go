@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/middleware"
+	"event-booking-be/internal/ratelimit"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rateLimitedApp(store ratelimit.Store, limit ratelimit.Limit) *fiber.App {
+	app := fiber.New()
+	app.Post("/login", middleware.RateLimit(store, "auth", limit), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+	app.Post("/bookings", middleware.AuthMiddleware(), middleware.RateLimit(store, "bookings", limit), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+	return app
+}
+
+func post(t *testing.T, app *fiber.App, path, userID string) *http.Response {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if userID != "" {
+		req.Header.Set("X-User-ID", userID)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestRateLimit_RejectsOverLimitWithHeaders(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	app := rateLimitedApp(ratelimit.NewRedisStore(client, "test:"), ratelimit.Limit{Requests: 2, Window: time.Minute})
+
+	for i, remaining := range []string{"1", "0"} {
+		resp := post(t, app, "/login", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode, "request %d", i)
+		assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+		assert.Equal(t, remaining, resp.Header.Get("RateLimit-Remaining"))
+	}
+
+	resp := post(t, app, "/login", "")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get(fiber.HeaderRetryAfter))
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+
+	var body handler.Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.False(t, body.Success)
+	assert.Equal(t, utils.RATE_LIMITED, body.Code)
+}
+
+func TestRateLimit_KeysAuthenticatedRequestsByUser(t *testing.T) {
+	app := rateLimitedApp(ratelimit.NewMemoryStore(), ratelimit.Limit{Requests: 1, Window: time.Minute})
+
+	// every test request shares an IP; users still get their own allowance
+	assert.Equal(t, http.StatusOK, post(t, app, "/bookings", "1").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, post(t, app, "/bookings", "1").StatusCode)
+	assert.Equal(t, http.StatusOK, post(t, app, "/bookings", "2").StatusCode)
+
+	// route groups are counted separately
+	assert.Equal(t, http.StatusOK, post(t, app, "/login", "").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, post(t, app, "/login", "").StatusCode)
+}
+
+func TestRateLimit_EventWritesAreLimitedPerUser(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, &handler.BookingHandler{},
+		&handler.WebhookHandler{}, &handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{},
+		nil, ratelimit.NewMemoryStore(), routes.RateLimits{
+			Events:      ratelimit.Limit{Requests: 100, Window: time.Minute},
+			EventWrites: ratelimit.Limit{Requests: 1, Window: time.Minute},
+		}).Setup(app)
+
+	// the body is invalid, so the handler answers without a service
+	assert.NotEqual(t, http.StatusTooManyRequests, post(t, app, "/api/v1/events", "1").StatusCode)
+	resp := post(t, app, "/api/v1/events", "1")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Limit"))
+	assert.NotEqual(t, http.StatusTooManyRequests, post(t, app, "/api/v1/events", "2").StatusCode)
+}
+
+func TestRateLimit_WindowSlides(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	limit := ratelimit.Limit{Requests: 1, Window: 100 * time.Millisecond}
+	ctx := context.Background()
+
+	for name, store := range map[string]ratelimit.Store{
+		"redis":  ratelimit.NewRedisStore(client, "test:"),
+		"memory": ratelimit.NewMemoryStore(),
+	} {
+		res, err := store.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed, name)
+
+		res, err = store.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		assert.False(t, res.Allowed, name)
+		assert.Greater(t, res.Reset, time.Duration(0), name)
+
+		time.Sleep(120 * time.Millisecond)
+		res, err = store.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed, name)
+	}
+}
+
+func TestRateLimit_FallsBackToMemoryWhenRedisIsDown(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	store := ratelimit.NewFallbackStore(ratelimit.NewRedisStore(client, "test:"), ratelimit.NewMemoryStore(), testLogger)
+	app := rateLimitedApp(store, ratelimit.Limit{Requests: 1, Window: time.Minute})
+
+	mr.Close()
+
+	assert.Equal(t, http.StatusOK, post(t, app, "/login", "").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, post(t, app, "/login", "").StatusCode)
+}
+
+func TestRateLimit_ParseLimit(t *testing.T) {
+	limit, err := ratelimit.ParseLimit("10/1m")
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Limit{Requests: 10, Window: time.Minute}, limit)
+
+	limit, err = ratelimit.ParseLimit("0")
+	require.NoError(t, err)
+	assert.True(t, limit.Unlimited())
+
+	for _, bad := range []string{"10", "x/1m", "10/soon", "10/0s"} {
+		_, err := ratelimit.ParseLimit(bad)
+		assert.Error(t, err, bad)
+	}
+}
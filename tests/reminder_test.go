@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/notification"
+	"event-booking-be/internal/outbox"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReminders_ScheduledRescheduledAndSentOnce(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	eventService := service.NewEventService(eventRepo, userRepo, outboxRepo, repository.NewAuditRepository(db), db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
+
+	renderer, err := notification.NewRenderer("en")
+	require.NoError(t, err)
+	mailer := notification.NewMemoryMailer()
+	notificationService := service.NewNotificationService(bookingRepo, eventRepo, userRepo, jobRepo, renderer, mailer)
+	reminderService := service.NewReminderService(reminderRepo, eventRepo, notificationService,
+		[]time.Duration{7 * 24 * time.Hour, 2 * time.Hour}, 3, testLogger)
+
+	relay := outbox.NewRelay(outboxRepo, db, []outbox.Sink{
+		outbox.NewDispatchSink("reminders", reminderService.Dispatch),
+	}, outbox.RelayConfig{})
+
+	event, err := eventService.CreateEvent(ctx, 1, &models.CreateEventRequest{
+		Name:         "Reminder Event",
+		DateTime:     time.Now().Add(3 * 24 * time.Hour),
+		TotalTickets: 10,
+		TicketPrice:  10.0,
+	})
+	require.NoError(t, err)
+
+	user := &models.User{Name: "Rita", Email: "rita-reminders@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	booking, err := bookingService.CreateBooking(ctx, user.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+	require.NoError(t, bookingService.ConfirmPayment(ctx, user.ID, booking.ID))
+	require.NoError(t, relay.Drain(ctx))
+
+	// the event is 3 days out, so only the 2h reminder is scheduled
+	reminders, err := reminderRepo.GetByEventID(ctx, event.ID)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.Equal(t, 120, reminders[0].OffsetMinutes)
+	assert.WithinDuration(t, event.DateTime.Add(-2*time.Hour), reminders[0].SendAt, time.Second)
+
+	// moving the event reschedules the reminder
+	newDate := time.Now().Add(90 * time.Minute)
+	_, err = eventService.UpdateEvent(ctx, 1, event.ID, &models.UpdateEventRequest{DateTime: &newDate})
+	require.NoError(t, err)
+	require.NoError(t, relay.Drain(ctx))
+
+	reminders, err = reminderRepo.GetByEventID(ctx, event.ID)
+	require.NoError(t, err)
+	require.Len(t, reminders, 1)
+	assert.WithinDuration(t, newDate.Add(-2*time.Hour), reminders[0].SendAt, time.Second)
+	assert.Equal(t, models.ReminderStatusPending, reminders[0].Status)
+
+	// several workers racing for the now-due reminder send it once
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = reminderService.ProcessDue(ctx)
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, reminderService.ProcessDue(ctx))
+
+	var sent []*notification.Message
+	for _, msg := range mailer.Messages() {
+		if msg.To == user.Email {
+			sent = append(sent, msg)
+		}
+	}
+	require.Len(t, sent, 1)
+	assert.Equal(t, "Reminder: Reminder Event is coming up", sent[0].Subject)
+
+	reminders, err = reminderRepo.GetByEventID(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReminderStatusSent, reminders[0].Status)
+	assert.Equal(t, 1, reminders[0].Attempts)
+}
+
+// interruptingMailer runs onSend in the middle of a send, standing in for
+// whatever else happens while the mail server is slow to answer.
+type interruptingMailer struct {
+	notification.Mailer
+	onSend func()
+	err    error
+}
+
+func (m *interruptingMailer) Send(ctx context.Context, msg *notification.Message) error {
+	if m.onSend != nil {
+		m.onSend()
+	}
+	if m.err != nil {
+		return m.err
+	}
+	return m.Mailer.Send(ctx, msg)
+}
+
+func TestReminders_ChangesDuringSendAreKept(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+
+	renderer, err := notification.NewRenderer("en")
+	require.NoError(t, err)
+	mailer := &interruptingMailer{Mailer: notification.NewMemoryMailer()}
+	notificationService := service.NewNotificationService(bookingRepo, eventRepo, userRepo, repository.NewJobRepository(db), renderer, mailer)
+	reminderService := service.NewReminderService(reminderRepo, eventRepo, notificationService,
+		[]time.Duration{2 * time.Hour}, 3, testLogger)
+
+	user := &models.User{Name: "Rudi", Email: "rudi-reminders@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+	event := &models.Event{Name: "Busy Event", DateTime: time.Now().Add(time.Hour), TotalTickets: 10, TicketPrice: 10}
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	dueReminder := func(status models.BookingStatus) *models.Reminder {
+		booking := &models.Booking{UserID: user.ID, EventID: event.ID, TicketCount: 1, TotalPrice: 10, Status: status}
+		require.NoError(t, bookingRepo.Create(ctx, booking))
+		reminder := &models.Reminder{BookingID: booking.ID, OffsetMinutes: 120, EventID: event.ID,
+			SendAt: time.Now().Add(-time.Minute), Status: models.ReminderStatusPending}
+		require.NoError(t, reminderRepo.CreateMany(ctx, []*models.Reminder{reminder}))
+		return reminder
+	}
+	stored := func(reminder *models.Reminder) *models.Reminder {
+		var current models.Reminder
+		require.NoError(t, db.First(&current, reminder.ID).Error)
+		return &current
+	}
+
+	// a booking that's no longer confirmed gets no reminder
+	expired := dueReminder(models.BookingStatusExpired)
+	mailer.onSend = func() { t.Error("reminder sent for an expired booking") }
+	require.NoError(t, reminderService.ProcessDue(ctx))
+	assert.Equal(t, models.ReminderStatusCancelled, stored(expired).Status)
+
+	// a cancellation during a failing send isn't turned back into a retry
+	cancelled := dueReminder(models.BookingStatusConfirmed)
+	mailer.onSend = func() { require.NoError(t, reminderRepo.CancelByBookingID(ctx, cancelled.BookingID)) }
+	mailer.err = errors.New("mail server busy")
+	require.NoError(t, reminderService.ProcessDue(ctx))
+	assert.Equal(t, models.ReminderStatusCancelled, stored(cancelled).Status)
+
+	// moving the event during a send keeps the reminder for the new date
+	moved := dueReminder(models.BookingStatusConfirmed)
+	newDate := time.Now().Add(5 * time.Hour)
+	payload, err := json.Marshal(&models.EventUpdatedPayload{EventID: event.ID, DateTime: newDate, PreviousDateTime: event.DateTime})
+	require.NoError(t, err)
+	mailer.onSend = func() {
+		require.NoError(t, reminderService.Dispatch(ctx, &models.OutboxMessage{
+			AggregateType: models.AggregateTypeEvent,
+			AggregateID:   event.ID,
+			EventType:     models.DomainEventEventUpdated,
+			Payload:       string(payload),
+		}))
+	}
+	mailer.err = nil
+	require.NoError(t, reminderService.ProcessDue(ctx))
+
+	rescheduled := stored(moved)
+	assert.Equal(t, models.ReminderStatusPending, rescheduled.Status)
+	assert.Equal(t, 0, rescheduled.Attempts)
+	assert.Nil(t, rescheduled.SentAt)
+	assert.WithinDuration(t, newDate.Add(-2*time.Hour), rescheduled.SendAt, time.Second)
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/validation"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketTypes_PriceBookings(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	eventService := service.NewEventService(eventRepo, userRepo, repository.NewOutboxRepository(db), repository.NewAuditRepository(db), db)
+	bookingService := service.NewBookingService(repository.NewBookingRepository(db), eventRepo, userRepo, repository.NewOutboxRepository(db),
+		repository.NewJobRepository(db), repository.NewAuditRepository(db), db, 15, testLogger)
+
+	organizer := &models.User{Name: "Tia", Email: "tia-types@test.com"}
+	require.NoError(t, userRepo.Create(ctx, organizer))
+	buyer := &models.User{Name: "Ben", Email: "ben-types@test.com"}
+	require.NoError(t, userRepo.Create(ctx, buyer))
+
+	event, err := eventService.CreateEvent(ctx, organizer.ID, &models.CreateEventRequest{
+		Name: "Tiered Gig", DateTime: time.Now().Add(24 * time.Hour), TotalTickets: 10, TicketPrice: 20,
+		TicketTypes: []models.TicketTypeRequest{{Name: "VIP", Price: 75}, {Name: "Student", Price: 10}},
+	})
+	require.NoError(t, err)
+
+	vip, err := bookingService.CreateBooking(ctx, buyer.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 2, TicketType: "VIP"})
+	require.NoError(t, err)
+	assert.Equal(t, "VIP", vip.TicketType)
+	assert.Equal(t, 150.0, vip.TotalPrice)
+
+	standard, err := bookingService.CreateBooking(ctx, buyer.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "", standard.TicketType)
+	assert.Equal(t, 20.0, standard.TotalPrice)
+
+	_, err = bookingService.CreateBooking(ctx, buyer.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1, TicketType: "Backstage"})
+	assert.ErrorIs(t, err, apperrors.ErrUnknownTicketType)
+
+	// every type draws on the same tickets
+	current, err := eventService.GetEvent(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 7, current.TotalTickets)
+	require.Len(t, current.TicketTypes, 2)
+	assert.Equal(t, "VIP", current.TicketTypes[0].Name)
+
+	// an update replaces the list; bookings keep the type they were sold as
+	replaced := []models.TicketTypeRequest{{Name: "Early Bird", Price: 15}}
+	updated, err := eventService.UpdateEvent(ctx, organizer.ID, event.ID, &models.UpdateEventRequest{TicketTypes: &replaced})
+	require.NoError(t, err)
+	require.Len(t, updated.TicketTypes, 1)
+	_, err = bookingService.CreateBooking(ctx, buyer.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1, TicketType: "VIP"})
+	assert.ErrorIs(t, err, apperrors.ErrUnknownTicketType)
+	current, err = eventService.GetEvent(ctx, event.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []models.TicketType{{ID: current.TicketTypes[0].ID, EventID: event.ID, Name: "Early Bird", Price: 15}}, current.TicketTypes)
+
+	duplicate := validation.Struct(&models.CreateEventRequest{
+		Name: "Dupes", DateTime: time.Now().Add(time.Hour), TotalTickets: 1,
+		TicketTypes: []models.TicketTypeRequest{{Name: "VIP", Price: 1}, {Name: "VIP", Price: 2}},
+	})
+	require.Error(t, duplicate)
+	assert.Equal(t, validation.FieldError{Field: "ticket_types", Code: "DUPLICATE", Message: "must not repeat a name"},
+		duplicate.(validation.Errors)[0])
+}
+
+func TestCheckIn(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db)
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, repository.NewOutboxRepository(db),
+		repository.NewJobRepository(db), repository.NewAuditRepository(db), db, 15, testLogger)
+
+	newUser := func(email string) *models.User {
+		user := &models.User{Name: email, Email: email}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+	organizer := newUser("organizer-checkin@test.com")
+	holder := newUser("holder-checkin@test.com")
+	stranger := newUser("stranger-checkin@test.com")
+
+	event := &models.Event{Name: "Door Test", DateTime: time.Now().Add(time.Hour), TotalTickets: 10, TicketPrice: 5, OrganizerID: &organizer.ID}
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	confirmed, err := bookingService.CreateBooking(ctx, holder.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+	require.NoError(t, bookingService.ConfirmPayment(ctx, holder.ID, confirmed.ID))
+	pending, err := bookingService.CreateBooking(ctx, holder.ID, &models.CreateBookingRequest{EventID: event.ID, TicketCount: 1})
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, handler.NewBookingHandler(bookingService),
+		&handler.WebhookHandler{}, &handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{}, nil, nil, routes.RateLimits{}).Setup(app)
+
+	checkIn := func(userID, bookingID int) int {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/bookings/%d/check-in", bookingID), nil)
+		req.Header.Set("X-User-ID", strconv.Itoa(userID))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// holders can't let themselves in, and others don't learn the booking exists
+	assert.Equal(t, http.StatusForbidden, checkIn(holder.ID, confirmed.ID))
+	assert.Equal(t, http.StatusNotFound, checkIn(stranger.ID, confirmed.ID))
+
+	assert.Equal(t, http.StatusOK, checkIn(organizer.ID, confirmed.ID))
+	stored, err := bookingRepo.GetByID(ctx, confirmed.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.CheckedInAt)
+
+	_, err = bookingService.CheckIn(ctx, organizer.ID, confirmed.ID)
+	assert.ErrorIs(t, err, apperrors.ErrAlreadyCheckedIn)
+	_, err = bookingService.CheckIn(ctx, organizer.ID, pending.ID)
+	assert.ErrorIs(t, err, apperrors.ErrNotConfirmed)
+	assert.Equal(t, http.StatusConflict, checkIn(organizer.ID, pending.ID))
+}
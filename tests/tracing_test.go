@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/tracing"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing_RequestSpansReachServiceDatabaseAndOutbox(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(previous)
+
+	db := setupTestDB(t)
+	require.NoError(t, tracing.InstrumentGORM(db))
+	ctx := context.Background()
+
+	eventRepo := repository.NewEventRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	bookingService := service.TraceBookingService(service.NewBookingService(repository.NewBookingRepository(db),
+		eventRepo, userRepo, outboxRepo, repository.NewJobRepository(db), repository.NewAuditRepository(db), db, 15, testLogger))
+
+	event := &models.Event{Name: "Traced Event", DateTime: time.Now().Add(time.Hour), TotalTickets: 10, TicketPrice: 1}
+	require.NoError(t, eventRepo.Create(ctx, event))
+	user := &models.User{Name: "Tia", Email: "tia-tracing@test.com"}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	app := fiber.New()
+	app.Use(tracing.Middleware())
+	app.Post("/bookings", func(c *fiber.Ctx) error {
+		c.Locals("userID", user.ID)
+		return c.Next()
+	}, handler.NewBookingHandler(bookingService).CreateBooking)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("POST", "/bookings",
+		strings.NewReader(fmt.Sprintf(`{"event_id":%d,"ticket_count":2}`, event.ID)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, 201, resp.StatusCode)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID().String() == traceID {
+			spans[span.Name()] = span
+		}
+	}
+
+	server, ok := spans["POST /bookings"]
+	require.True(t, ok, "server span continues the caller's trace")
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+
+	serviceSpan, ok := spans["BookingService.CreateBooking"]
+	require.True(t, ok)
+	assert.Equal(t, server.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+
+	queries := 0
+	for _, span := range recorder.Ended() {
+		if span.Parent().SpanID() == serviceSpan.SpanContext().SpanID() {
+			queries++
+		}
+	}
+	assert.Greater(t, queries, 0, "repository queries nest under the service span")
+
+	// the outbox message carries the trace on to the relay
+	var message models.OutboxMessage
+	require.NoError(t, db.Where("event_type = ?", models.DomainEventBookingCreated).
+		Order("id DESC").First(&message).Error)
+	assert.Contains(t, message.TraceParent, traceID)
+}
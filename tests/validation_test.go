@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"encoding/json"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/utils"
+	"event-booking-be/internal/validation"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidation_CreateEventListsEveryFieldError(t *testing.T) {
+	db := setupTestDB(t)
+	eventService := service.NewEventService(repository.NewEventRepository(db), repository.NewUserRepository(db), repository.NewOutboxRepository(db), repository.NewAuditRepository(db), db)
+
+	app := fiber.New()
+	app.Post("/events", func(c *fiber.Ctx) error {
+		c.Locals("userID", 1)
+		return c.Next()
+	}, handler.NewEventHandler(eventService).CreateEvent)
+
+	body := fmt.Sprintf(`{"name":"","date_time":%q,"total_tickets":-5,"ticket_price":-1}`,
+		time.Now().Add(-time.Hour).Format(time.RFC3339))
+	req := httptest.NewRequest("POST", "/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	var result struct {
+		Code    string                  `json:"code"`
+		Details []validation.FieldError `json:"details"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, utils.VALIDATION_FAILED, result.Code)
+
+	codes := map[string]string{}
+	for _, fe := range result.Details {
+		codes[fe.Field] = fe.Code
+	}
+	assert.Equal(t, map[string]string{
+		"name":          "REQUIRED",
+		"date_time":     "NOT_IN_FUTURE",
+		"total_tickets": "TOO_SMALL",
+		"ticket_price":  "TOO_SMALL",
+	}, codes)
+
+	var count int64
+	require.NoError(t, db.Model(&models.Event{}).Where("name = ''").Count(&count).Error)
+	assert.Zero(t, count, "invalid events never reach the database")
+}
+
+func TestValidation_DomainRules(t *testing.T) {
+	tooMany := validation.Struct(&models.CreateBookingRequest{EventID: 1, TicketCount: models.MaxTicketsPerOrder + 1})
+	require.Error(t, tooMany)
+	assert.Equal(t, "TOO_LARGE", tooMany.(validation.Errors)[0].Code)
+	assert.NoError(t, validation.Struct(&models.CreateBookingRequest{EventID: 1, TicketCount: models.MaxTicketsPerOrder}))
+
+	badEmail := validation.Struct(&models.CreateUserRequest{Name: "Val", Email: "not-an-email"})
+	require.Error(t, badEmail)
+	assert.Equal(t, validation.FieldError{Field: "email", Code: "INVALID_EMAIL", Message: "must be a valid email address"},
+		badEmail.(validation.Errors)[0])
+
+	// partial updates only check the fields that are present
+	past := time.Now().Add(-time.Hour)
+	assert.NoError(t, validation.Struct(&models.UpdateEventRequest{}))
+	assert.Error(t, validation.Struct(&models.UpdateEventRequest{DateTime: &past}))
+
+	// free events are allowed
+	assert.NoError(t, validation.Struct(&models.CreateEventRequest{
+		Name: "Free", DateTime: time.Now().Add(time.Hour), TotalTickets: 1,
+	}))
+}
@@ -0,0 +1,315 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"event-booking-be/internal/apperrors"
+	"event-booking-be/internal/handler"
+	"event-booking-be/internal/models"
+	"event-booking-be/internal/outbox"
+	"event-booking-be/internal/repository"
+	"event-booking-be/internal/routes"
+	"event-booking-be/internal/service"
+	"event-booking-be/internal/validation"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedWebhook struct {
+	eventType string
+	signature string
+	body      []byte
+}
+
+// webhookStandIn is a local HTTP receiver that records requests and answers
+// with the configured status code.
+type webhookStandIn struct {
+	mu        sync.Mutex
+	status    int
+	received  []receivedWebhook
+	onRequest func()
+}
+
+func (s *webhookStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if s.onRequest != nil {
+		s.onRequest()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = append(s.received, receivedWebhook{
+		eventType: r.Header.Get("X-Webhook-Event"),
+		signature: r.Header.Get("X-Webhook-Signature"),
+		body:      body,
+	})
+	w.WriteHeader(s.status)
+}
+
+func verifySignature(t *testing.T, secret string, webhook receivedWebhook) {
+	parts := strings.Split(webhook.signature, ",")
+	require.Len(t, parts, 2)
+
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "t="), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, "v1="+service.SignWebhookPayload(secret, timestamp, webhook.body), parts[1])
+}
+
+func TestWebhooks_BookingCreatedIsDeliveredSigned(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	standIn := &webhookStandIn{status: http.StatusOK}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	bookingRepo := repository.NewBookingRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	eventService := service.NewEventService(eventRepo, userRepo, outboxRepo, repository.NewAuditRepository(db), db)
+	jobRepo := repository.NewJobRepository(db)
+	bookingService := service.NewBookingService(bookingRepo, eventRepo, userRepo, outboxRepo, jobRepo, repository.NewAuditRepository(db), db, 15, testLogger)
+	webhookService := service.NewWebhookService(webhookRepo, eventRepo, db, server.Client(), service.WebhookConfig{AllowPrivateNetworks: true})
+
+	organizer := &models.User{Name: "Org", Email: "org-webhooks@test.com"}
+	require.NoError(t, userRepo.Create(ctx, organizer))
+	attendee := &models.User{Name: "Ann", Email: "ann-webhooks@test.com"}
+	require.NoError(t, userRepo.Create(ctx, attendee))
+
+	event, err := eventService.CreateEvent(ctx, organizer.ID, &models.CreateEventRequest{
+		Name:         "Webhook Event",
+		DateTime:     time.Now().Add(24 * time.Hour),
+		TotalTickets: 10,
+		TicketPrice:  10.0,
+	})
+	require.NoError(t, err)
+
+	subscription, err := webhookService.CreateSubscription(ctx, organizer.ID, &models.CreateWebhookRequest{
+		URL:        server.URL,
+		EventTypes: []string{models.DomainEventBookingCreated},
+	})
+	require.NoError(t, err)
+
+	// another organizer can't see the subscription
+	_, err = webhookService.GetSubscription(ctx, attendee.ID, subscription.ID)
+	assert.Error(t, err)
+
+	_, err = bookingService.CreateBooking(ctx, attendee.ID, &models.CreateBookingRequest{
+		EventID:     event.ID,
+		TicketCount: 2,
+	})
+	require.NoError(t, err)
+
+	relay := outbox.NewRelay(outboxRepo, db, []outbox.Sink{
+		outbox.NewDispatchSink("webhooks", webhookService.Dispatch),
+	}, outbox.RelayConfig{})
+	_, err = relay.ProcessBatch(ctx)
+	require.NoError(t, err)
+
+	// the claim is committed before the POST goes out, so no transaction is
+	// held open while the receiver answers
+	standIn.onRequest = func() {
+		var claimed int64
+		assert.NoError(t, db.Model(&models.WebhookDelivery{}).
+			Where("subscription_id = ? AND next_attempt_at > ?", subscription.ID, time.Now()).
+			Count(&claimed).Error)
+		assert.Equal(t, int64(1), claimed)
+	}
+	require.NoError(t, webhookService.ProcessDeliveries(ctx))
+
+	require.Len(t, standIn.received, 1)
+	webhook := standIn.received[0]
+	assert.Equal(t, models.DomainEventBookingCreated, webhook.eventType)
+	verifySignature(t, subscription.Secret, webhook)
+
+	var body struct {
+		Type string                     `json:"type"`
+		Data models.BookingEventPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(webhook.body, &body))
+	assert.Equal(t, models.DomainEventBookingCreated, body.Type)
+	assert.Equal(t, event.ID, body.Data.EventID)
+	assert.Equal(t, 2, body.Data.TicketCount)
+
+	deliveries, err := webhookService.GetDeliveries(ctx, organizer.ID, subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.WebhookDeliverySucceeded, deliveries[0].Status)
+}
+
+func TestWebhooks_FailingEndpointIsRetriedAndDisabled(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	standIn := &webhookStandIn{status: http.StatusInternalServerError}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookService := service.NewWebhookService(webhookRepo, eventRepo, db, server.Client(), service.WebhookConfig{
+		MaxAttempts:          5,
+		DisableAfterFailures: 2,
+		RetryBaseDelay:       time.Millisecond,
+		AllowPrivateNetworks: true,
+	})
+
+	organizerID := 9001
+	event := &models.Event{
+		Name:         "Flaky Event",
+		DateTime:     time.Now().Add(24 * time.Hour),
+		TotalTickets: 10,
+		TicketPrice:  10.0,
+		OrganizerID:  &organizerID,
+	}
+	require.NoError(t, eventRepo.Create(ctx, event))
+
+	subscription, err := webhookService.CreateSubscription(ctx, organizerID, &models.CreateWebhookRequest{
+		URL: server.URL,
+	})
+	require.NoError(t, err)
+
+	message := &models.OutboxMessage{
+		ID:            int64(900000 + event.ID),
+		AggregateType: models.AggregateTypeEvent,
+		AggregateID:   event.ID,
+		EventType:     models.DomainEventEventUpdated,
+		Payload:       fmt.Sprintf(`{"event_id":%d}`, event.ID),
+	}
+	require.NoError(t, webhookService.Dispatch(ctx, message))
+	// a re-relayed message doesn't queue a second delivery
+	require.NoError(t, webhookService.Dispatch(ctx, message))
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, webhookService.ProcessDeliveries(ctx))
+	}
+
+	// two failed attempts disable the subscription; the third pass gives up
+	assert.Len(t, standIn.received, 2)
+
+	disabled, err := webhookService.GetSubscription(ctx, organizerID, subscription.ID)
+	require.NoError(t, err)
+	assert.False(t, disabled.Active)
+	assert.NotNil(t, disabled.DisabledAt)
+
+	deliveries, err := webhookService.GetDeliveries(ctx, organizerID, subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.WebhookDeliveryFailed, deliveries[0].Status)
+	assert.Equal(t, http.StatusInternalServerError, deliveries[0].ResponseStatus)
+
+	// the test endpoint still works on a disabled subscription
+	standIn.mu.Lock()
+	standIn.status = http.StatusNoContent
+	standIn.mu.Unlock()
+	delivery, err := webhookService.SendTestEvent(ctx, organizerID, subscription.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, models.WebhookEventTest, standIn.received[2].eventType)
+	verifySignature(t, subscription.Secret, standIn.received[2])
+}
+
+const webhookTestSecret = "s3cret-that-is-long-enough-to-sign-with"
+
+func TestWebhooks_SecretIsOnlyShownOnCreate(t *testing.T) {
+	db := setupTestDB(t)
+	webhookService := service.NewWebhookService(repository.NewWebhookRepository(db), repository.NewEventRepository(db), db,
+		http.DefaultClient, service.WebhookConfig{AllowPrivateNetworks: true}) // keeps the example host from being resolved
+
+	app := fiber.New(fiber.Config{ErrorHandler: handler.ErrorHandler})
+	routes.NewRouter(&handler.UserHandler{}, &handler.EventHandler{}, &handler.BookingHandler{}, handler.NewWebhookHandler(webhookService),
+		&handler.AvailabilityHandler{}, &handler.AuditHandler{}, &handler.AnalyticsHandler{}, &handler.CalendarHandler{}, nil, nil, routes.RateLimits{}).Setup(app)
+
+	call := func(method, path, body string) map[string]interface{} {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User-ID", "901")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		var envelope map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&envelope))
+		return envelope
+	}
+
+	created := call(http.MethodPost, "/api/v1/webhooks", `{"url":"https://hooks.example.com/in","secret":"`+webhookTestSecret+`"}`)["data"].(map[string]interface{})
+	assert.Equal(t, webhookTestSecret, created["secret"])
+
+	fetched := call(http.MethodGet, fmt.Sprintf("/api/v1/webhooks/%v", created["id"]), "")["data"].(map[string]interface{})
+	assert.Equal(t, created["id"], fetched["id"])
+	assert.NotContains(t, fetched, "secret")
+
+	for _, listed := range call(http.MethodGet, "/api/v1/webhooks", "")["data"].([]interface{}) {
+		assert.NotContains(t, listed, "secret")
+	}
+}
+
+func TestWebhooks_ShortSecretsAreRejected(t *testing.T) {
+	err := validation.Struct(&models.CreateWebhookRequest{URL: "https://hooks.example.com/in", Secret: "s3cret"})
+	require.Error(t, err)
+	assert.Equal(t, "secret", err.(validation.Errors)[0].Field)
+	assert.Equal(t, "TOO_SHORT", err.(validation.Errors)[0].Code)
+
+	assert.NoError(t, validation.Struct(&models.CreateWebhookRequest{URL: "https://hooks.example.com/in", Secret: webhookTestSecret}))
+}
+
+func TestWebhooks_PrivateAddressesAreRefused(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	standIn := &webhookStandIn{status: http.StatusOK}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	eventRepo := repository.NewEventRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookService := service.NewWebhookService(webhookRepo, eventRepo, db, nil, service.WebhookConfig{})
+
+	organizerID := 9002
+	for _, url := range []string{
+		server.URL,
+		"http://localhost:8080/hook",
+		"http://10.0.0.5/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+	} {
+		_, err := webhookService.CreateSubscription(ctx, organizerID, &models.CreateWebhookRequest{URL: url})
+		assert.ErrorIs(t, err, apperrors.ErrInvalidWebhook, url)
+	}
+
+	// a subscription whose host started resolving to a private address later
+	// is stopped when the delivery dials it
+	event := &models.Event{Name: "Rebound Event", DateTime: time.Now().Add(24 * time.Hour), TotalTickets: 10, OrganizerID: &organizerID}
+	require.NoError(t, eventRepo.Create(ctx, event))
+	subscription := &models.WebhookSubscription{OrganizerID: organizerID, URL: server.URL, Secret: webhookTestSecret, Active: true}
+	require.NoError(t, webhookRepo.CreateSubscription(ctx, subscription))
+
+	require.NoError(t, webhookService.Dispatch(ctx, &models.OutboxMessage{
+		ID:            int64(910000 + event.ID),
+		AggregateType: models.AggregateTypeEvent,
+		AggregateID:   event.ID,
+		EventType:     models.DomainEventEventUpdated,
+		Payload:       fmt.Sprintf(`{"event_id":%d}`, event.ID),
+	}))
+	require.NoError(t, webhookService.ProcessDeliveries(ctx))
+
+	assert.Empty(t, standIn.received)
+	deliveries, err := webhookService.GetDeliveries(ctx, organizerID, subscription.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.WebhookDeliveryPending, deliveries[0].Status)
+	assert.Contains(t, deliveries[0].LastError, "not public")
+}